@@ -0,0 +1,295 @@
+package cachex
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ARCCache 自适应替换缓存（Adaptive Replacement Cache），协程安全
+// t1 保存只访问过一次的条目，t2 保存访问过多次的条目，b1、b2 分别记录从 t1、t2 淘汰的键（只保留键）
+// 命中 b1 说明近期性更重要，扩大 t1 的目标大小；命中 b2 说明频率更重要，缩小 t1 的目标大小
+// 因此可以在扫描型和循环型负载之间自动调整
+type ARCCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	items   map[K]*list.Element
+	t1, t2  *list.List
+	b1, b2  *list.List
+	p       int // t1 的目标大小
+	opts    ARCCacheOption[K, V]
+	evicted []evictedEntry[K, V]
+}
+
+type ARCCacheOption[K comparable, V any] struct {
+	// Capacity 最大条目数，必须大于0
+	Capacity int
+	// OnEvict 条目被移除时的回调，在释放锁之后调用
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+type arcEntry[K comparable, V any] struct {
+	key   K
+	value V
+	list  *list.List
+}
+
+// NewARCCache 创建一个 ARC 缓存，Capacity 小于1时按1处理
+func NewARCCache[K comparable, V any](opts ARCCacheOption[K, V]) *ARCCache[K, V] {
+	if opts.Capacity < 1 {
+		opts.Capacity = 1
+	}
+	return &ARCCache[K, V]{
+		items: make(map[K]*list.Element),
+		t1:    list.New(),
+		t2:    list.New(),
+		b1:    list.New(),
+		b2:    list.New(),
+		opts:  opts,
+	}
+}
+
+// Get 获取键对应的值，命中的条目会移到 t2
+func (c *ARCCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		var zero V
+		return zero, false
+	}
+	c.move(elem, c.t2)
+	return elem.Value.(*arcEntry[K, V]).value, true
+}
+
+// Peek 获取键对应的值，不改变条目位置
+func (c *ARCCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok || !c.resident(elem) {
+		var zero V
+		return zero, false
+	}
+	return elem.Value.(*arcEntry[K, V]).value, true
+}
+
+// Set 添加或更新键值对
+func (c *ARCCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	capacity := c.opts.Capacity
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*arcEntry[K, V])
+		switch entry.list {
+		case c.t1, c.t2:
+			entry.value = value
+			c.move(elem, c.t2)
+			return
+		case c.b1:
+			// 命中 b1，增大 t1 的目标大小
+			c.p = minInt(capacity, c.p+maxInt(c.b2.Len()/c.b1.Len(), 1))
+			c.replace(false)
+		case c.b2:
+			// 命中 b2，减小 t1 的目标大小
+			c.p = maxInt(0, c.p-maxInt(c.b1.Len()/c.b2.Len(), 1))
+			c.replace(true)
+		}
+		entry.value = value
+		c.move(elem, c.t2)
+		return
+	}
+
+	l1 := c.t1.Len() + c.b1.Len()
+	total := l1 + c.t2.Len() + c.b2.Len()
+	if l1 >= capacity {
+		if c.t1.Len() < capacity {
+			c.drop(c.b1.Back())
+			c.replace(false)
+		} else {
+			// t1 已占满整个缓存，直接删除 t1 最久未访问的条目，不记入 b1
+			c.discard(c.t1.Back())
+		}
+	} else if total >= capacity {
+		if total >= 2*capacity {
+			c.drop(c.b2.Back())
+		}
+		c.replace(false)
+	}
+	c.items[key] = c.t1.PushFront(&arcEntry[K, V]{key: key, value: value, list: c.t1})
+}
+
+// Remove 删除键值对，返回键是否存在
+func (c *ARCCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	resident := c.resident(elem)
+	entry := elem.Value.(*arcEntry[K, V])
+	entry.list.Remove(elem)
+	delete(c.items, key)
+	if resident && c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evictedEntry[K, V]{key: key, value: entry.value, reason: EvictDeleted})
+	}
+	return resident
+}
+
+// Resize 修改容量，返回因容量缩小而被淘汰的条目数，capacity 小于1时按1处理
+// 淘汰的条目仍记入历史列表，历史列表超出新容量的部分被删除
+func (c *ARCCache[K, V]) Resize(capacity int) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if capacity < 1 {
+		capacity = 1
+	}
+	c.opts.Capacity = capacity
+	c.p = minInt(c.p, capacity)
+	n := 0
+	for c.t1.Len()+c.t2.Len() > capacity {
+		c.replace(false)
+		n++
+	}
+	for c.b1.Len() > 0 && c.t1.Len()+c.b1.Len() > capacity {
+		c.drop(c.b1.Back())
+	}
+	for c.b2.Len() > 0 && c.t1.Len()+c.t2.Len()+c.b1.Len()+c.b2.Len() > 2*capacity {
+		c.drop(c.b2.Back())
+	}
+	return n
+}
+
+// Len 返回缓存中的条目数，不包含只记录了键的淘汰历史
+func (c *ARCCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t1.Len() + c.t2.Len()
+}
+
+// Keys 返回缓存中所有的键，先 t2 后 t1，各自按从新到旧的访问顺序，不包含只记录了键的淘汰历史
+func (c *ARCCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, c.t1.Len()+c.t2.Len())
+	for _, l := range []*list.List{c.t2, c.t1} {
+		for elem := l.Front(); elem != nil; elem = elem.Next() {
+			keys = append(keys, elem.Value.(*arcEntry[K, V]).key)
+		}
+	}
+	return keys
+}
+
+// Clear 清空缓存和淘汰历史，每个条目都会触发 OnEvict
+func (c *ARCCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.opts.OnEvict != nil {
+		for _, l := range []*list.List{c.t1, c.t2} {
+			for elem := l.Back(); elem != nil; elem = elem.Prev() {
+				entry := elem.Value.(*arcEntry[K, V])
+				c.evicted = append(c.evicted, evictedEntry[K, V]{key: entry.key, value: entry.value, reason: EvictDeleted})
+			}
+		}
+	}
+	c.items = make(map[K]*list.Element)
+	c.t1.Init()
+	c.t2.Init()
+	c.b1.Init()
+	c.b2.Init()
+	c.p = 0
+}
+
+// unlock 释放锁，并通知持有锁期间被移除的条目
+func (c *ARCCache[K, V]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+	for _, e := range evicted {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+func (c *ARCCache[K, V]) resident(elem *list.Element) bool {
+	l := elem.Value.(*arcEntry[K, V]).list
+	return l == c.t1 || l == c.t2
+}
+
+// replace 从 t1 或 t2 淘汰一个条目到对应的历史列表，缓存未满时（例如 Remove 之后）不淘汰
+func (c *ARCCache[K, V]) replace(inB2 bool) {
+	t1 := c.t1.Len()
+	if t1+c.t2.Len() < c.opts.Capacity {
+		return
+	}
+	if t1 > 0 && (t1 > c.p || (inB2 && t1 == c.p) || c.t2.Len() == 0) {
+		c.evict(c.t1.Back())
+	} else if c.t2.Len() > 0 {
+		c.evict(c.t2.Back())
+	}
+}
+
+// evict 淘汰条目的值，只在历史列表中保留键
+func (c *ARCCache[K, V]) evict(elem *list.Element) {
+	entry := elem.Value.(*arcEntry[K, V])
+	ghost := c.b1
+	if entry.list == c.t2 {
+		ghost = c.b2
+	}
+	if c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evictedEntry[K, V]{key: entry.key, value: entry.value, reason: EvictCapacity})
+	}
+	var zero V
+	entry.value = zero
+	c.move(elem, ghost)
+}
+
+// discard 淘汰条目并彻底删除，不在历史列表中保留键
+func (c *ARCCache[K, V]) discard(elem *list.Element) {
+	entry := elem.Value.(*arcEntry[K, V])
+	if c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evictedEntry[K, V]{key: entry.key, value: entry.value, reason: EvictCapacity})
+	}
+	c.drop(elem)
+}
+
+// drop 彻底删除历史列表中的键
+func (c *ARCCache[K, V]) drop(elem *list.Element) {
+	if elem == nil {
+		return
+	}
+	entry := elem.Value.(*arcEntry[K, V])
+	entry.list.Remove(elem)
+	delete(c.items, entry.key)
+}
+
+// move 将条目移动到目标列表的队首
+func (c *ARCCache[K, V]) move(elem *list.Element, dst *list.List) {
+	entry := elem.Value.(*arcEntry[K, V])
+	if entry.list == dst {
+		dst.MoveToFront(elem)
+		return
+	}
+	entry.list.Remove(elem)
+	entry.list = dst
+	c.items[entry.key] = dst.PushFront(entry)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,147 @@
+package cachex
+
+import (
+	"testing"
+)
+
+func TestARCCache(t *testing.T) {
+	evicted := 0
+	cache := NewARCCache(ARCCacheOption[int, int]{
+		Capacity: 10,
+		OnEvict: func(key int, value int, reason EvictReason) {
+			evicted++
+		},
+	})
+
+	// 频繁访问的键进入 t2
+	for i := 0; i < 5; i++ {
+		cache.Set(i, i)
+		cache.Get(i)
+	}
+	// 扫描只访问一次的键，只会挤占 t1
+	for i := 100; i < 200; i++ {
+		cache.Set(i, i)
+	}
+	for i := 0; i < 5; i++ {
+		if v, ok := cache.Get(i); !ok || v != i {
+			t.Errorf("Expected frequent key %d to survive the scan, got %v, ok: %v", i, v, ok)
+		}
+	}
+	if n := cache.Len(); n != 10 {
+		t.Errorf("Expected 10 entries, got %d", n)
+	}
+	if evicted != 95 {
+		t.Errorf("Expected 95 evictions, got %d", evicted)
+	}
+
+	historyLen := func() int {
+		return cache.b1.Len() + cache.b2.Len()
+	}
+	if historyLen() == 0 || historyLen() > 10 {
+		t.Errorf("Expected bounded ghost history, got %d", historyLen())
+	}
+
+	// 命中 b1 后增大 t1 的目标大小
+	ghost := cache.b1.Front().Value.(*arcEntry[int, int]).key
+	p := cache.p
+	cache.Set(ghost, -1)
+	if cache.p <= p {
+		t.Errorf("Expected p to grow after ghost hit, got %d -> %d", p, cache.p)
+	}
+	if v, ok := cache.Peek(ghost); !ok || v != -1 {
+		t.Errorf("Expected -1, got %v, ok: %v", v, ok)
+	}
+
+	if !cache.Remove(ghost) || cache.Remove(ghost) {
+		t.Errorf("Expected Remove to report existence")
+	}
+	if total := len(cache.items); total > 20 {
+		t.Errorf("Expected at most 20 tracked keys, got %d", total)
+	}
+}
+
+func TestARCCacheScanBounded(t *testing.T) {
+	const capacity = 10
+	evicted := 0
+	cache := NewARCCache(ARCCacheOption[int, int]{
+		Capacity: capacity,
+		OnEvict: func(key int, value int, reason EvictReason) {
+			evicted++
+		},
+	})
+
+	for i := 0; i < 100000; i++ {
+		cache.Set(i, i)
+	}
+	if ghosts := cache.b1.Len() + cache.b2.Len(); ghosts > capacity {
+		t.Errorf("Expected at most %d ghost keys after a scan, got %d", capacity, ghosts)
+	}
+	if total := len(cache.items); total > 2*capacity {
+		t.Errorf("Expected at most %d tracked keys after a scan, got %d", 2*capacity, total)
+	}
+	if n := cache.Len(); n != capacity {
+		t.Errorf("Expected %d entries, got %d", capacity, n)
+	}
+	if evicted != 100000-capacity {
+		t.Errorf("Expected %d evictions, got %d", 100000-capacity, evicted)
+	}
+}
+
+func TestARCCacheGhostHitNotFull(t *testing.T) {
+	cache := NewARCCache(ARCCacheOption[int, int]{Capacity: 4})
+	for i := 0; i < 4; i++ {
+		cache.Set(i, i)
+		cache.Get(i)
+	}
+	// 0 被淘汰到 b2
+	cache.Set(4, 4)
+	cache.Remove(1)
+	cache.Remove(2)
+
+	// 缓存未满时命中历史不应再淘汰其他条目
+	before := cache.Len()
+	cache.Set(0, 0)
+	if n := cache.Len(); n != before+1 {
+		t.Errorf("Expected ghost hit to add an entry without eviction, got %d -> %d", before, n)
+	}
+}
+
+func TestARCCacheResizeKeysClear(t *testing.T) {
+	var deleted int
+	cache := NewARCCache(ARCCacheOption[int, int]{
+		Capacity: 4,
+		OnEvict: func(key int, value int, reason EvictReason) {
+			if reason == EvictDeleted {
+				deleted++
+			}
+		},
+	})
+	for i := 0; i < 4; i++ {
+		cache.Set(i, i)
+	}
+	cache.Get(0)
+	// t2 中的键排在前面
+	if keys := cache.Keys(); len(keys) != 4 || keys[0] != 0 || keys[1] != 3 {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+
+	if n := cache.Resize(2); n != 2 || cache.Len() != 2 {
+		t.Errorf("Expected Resize to evict 2 entries, got %d, len %d", n, cache.Len())
+	}
+	if total := len(cache.items); total > 4 {
+		t.Errorf("Expected at most 4 tracked keys after shrinking, got %d", total)
+	}
+	if _, ok := cache.Get(0); !ok {
+		t.Errorf("Expected frequent key to survive the resize")
+	}
+	cache.Resize(3)
+	cache.Set(10, 10)
+	if cache.Len() != 3 {
+		t.Errorf("Expected the larger capacity to be used, got %d", cache.Len())
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 || len(cache.Keys()) != 0 || len(cache.items) != 0 || deleted != 3 {
+		t.Errorf("Expected Clear to remove everything, len %d, tracked %d, deleted %d", cache.Len(), len(cache.items), deleted)
+	}
+}
@@ -0,0 +1,110 @@
+package cachex
+
+import (
+	"math"
+	"time"
+)
+
+// AutoScale 容量自动调整策略，每个采样周期根据命中率和容量淘汰调整一次 MaxEntries
+// 命中率低于目标且发生过容量淘汰时按 Factor 扩容，没有容量淘汰且缩容后仍能容纳所有条目时按 Factor 缩容
+type AutoScale struct {
+	// Min Max MaxEntries 的调整范围，Max 小于等于0时不启用
+	Min int
+	Max int
+	// Interval 采样周期，小于等于0时为1分钟
+	Interval time.Duration
+	// TargetHitRatio 目标命中率，小于等于0时为0.9
+	TargetHitRatio float64
+	// Factor 每次扩容或缩容的倍数，小于等于1时为1.25
+	Factor float64
+	// OnResize 调整 MaxEntries 后的回调
+	OnResize func(old, new int)
+}
+
+func (a AutoScale) enabled() bool {
+	return a.Max > 0
+}
+
+func (a AutoScale) withDefaults() AutoScale {
+	if a.Interval <= 0 {
+		a.Interval = time.Minute
+	}
+	if a.TargetHitRatio <= 0 {
+		a.TargetHitRatio = 0.9
+	}
+	if a.Factor <= 1 {
+		a.Factor = 1.25
+	}
+	if a.Min < 1 {
+		a.Min = 1
+	}
+	return a
+}
+
+// next 根据一个采样周期内的命中、未命中和容量淘汰次数计算新的上限
+func (a AutoScale) next(cur, size int, hits, misses, evictions uint64) int {
+	n := cur
+	switch {
+	case evictions > 0 && hits+misses > 0 && float64(hits)/float64(hits+misses) < a.TargetHitRatio:
+		n = int(math.Ceil(float64(cur) * a.Factor))
+	case evictions == 0 && float64(size) < float64(cur)/a.Factor:
+		n = int(float64(cur) / a.Factor)
+	}
+	if n > a.Max {
+		n = a.Max
+	}
+	if n < a.Min {
+		n = a.Min
+	}
+	return n
+}
+
+// autoscale 定期采样统计数据并调整 MaxEntries，缓存销毁时退出
+func (c *BaseCache[K, V]) autoscale() {
+	a := c.opts.AutoScale.withDefaults()
+	ticker := c.opts.Clock.NewTicker(a.Interval)
+	defer ticker.Stop()
+	// 第一个周期从创建缓存时开始计算
+	var hits, misses, evictions uint64
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C():
+		}
+		h, m, e := c.stats.hits.Load(), c.stats.misses.Load(), c.stats.capacity.Load()
+		// ResetStats 会让计数器变小，此时以清零后的值作为这个周期的增量
+		if h < hits || m < misses || e < evictions {
+			hits, misses, evictions = 0, 0, 0
+		}
+		cur := c.MaxEntries()
+		if n := a.next(cur, c.Len(), h-hits, m-misses, e-evictions); n != cur {
+			c.SetMaxEntries(n)
+			if a.OnResize != nil {
+				a.OnResize(cur, n)
+			}
+		}
+		hits, misses, evictions = h, m, e
+	}
+}
+
+// MaxEntries 返回当前的条目数上限
+func (c *BaseCache[K, V]) MaxEntries() int {
+	return int(c.maxEntries.Load())
+}
+
+// SetMaxEntries 修改条目数上限，缩小时立即淘汰超出的条目
+// 只能调整创建时设置了 MaxEntries 的缓存，n 小于1或未设置 MaxEntries 时不做修改
+func (c *BaseCache[K, V]) SetMaxEntries(n int) {
+	if n < 1 || c.opts.MaxEntries <= 0 {
+		return
+	}
+	c.maxEntries.Store(int64(n))
+	per := int(ceilDiv(int64(n), int64(len(c.shards))))
+	for _, s := range c.shards {
+		s.lock()
+		s.maxEntries = per
+		s.evictOverflow()
+		s.unlock()
+	}
+}
@@ -0,0 +1,78 @@
+package cachex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAutoScaleNext(t *testing.T) {
+	a := AutoScale{Min: 10, Max: 100}.withDefaults()
+	tests := []struct {
+		name                    string
+		cur, size               int
+		hits, misses, evictions uint64
+		want                    int
+	}{
+		{"grow on low hit ratio with evictions", 40, 40, 50, 50, 10, 50},
+		{"keep when hit ratio is high", 40, 40, 95, 5, 10, 40},
+		{"keep low hit ratio without evictions", 40, 39, 1, 99, 0, 40},
+		{"shrink when entries fit", 40, 20, 95, 5, 0, 32},
+		{"clamp to max", 90, 90, 0, 10, 5, 100},
+		{"clamp to min", 11, 0, 0, 0, 0, 10},
+	}
+	for _, tt := range tests {
+		if got := a.next(tt.cur, tt.size, tt.hits, tt.misses, tt.evictions); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSetMaxEntries(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 10, Shards: 2})
+	defer cache.Close()
+	for i := 0; i < 10; i++ {
+		cache.Set(fmt.Sprint(i), i)
+	}
+	cache.SetMaxEntries(4)
+	if n := cache.Len(); n > 4 || cache.MaxEntries() != 4 {
+		t.Errorf("Expected at most 4 entries after shrinking, got %d", n)
+	}
+
+	unbounded := NewBaseCache(OnceCacheOption[string, int]{})
+	defer unbounded.Close()
+	unbounded.SetMaxEntries(4)
+	if n := unbounded.MaxEntries(); n != 0 {
+		t.Errorf("Expected unbounded cache to ignore SetMaxEntries, got %d", n)
+	}
+}
+
+func TestAutoScale(t *testing.T) {
+	resized := make(chan [2]int, 10)
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries: 10,
+		AutoScale: AutoScale{
+			Min:      10,
+			Max:      40,
+			Interval: 20 * time.Millisecond,
+			OnResize: func(old, new int) { resized <- [2]int{old, new} },
+		},
+	})
+	defer cache.Close()
+
+	// 工作集大于容量，命中率低且不断发生容量淘汰
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i % 30)
+		if _, ok := cache.Get(key); !ok {
+			cache.Set(key, i)
+		}
+	}
+	select {
+	case r := <-resized:
+		if r[0] != 10 || r[1] <= 10 {
+			t.Errorf("Expected cache to grow from 10, got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected AutoScale to grow the cache")
+	}
+}
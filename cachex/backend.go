@@ -0,0 +1,55 @@
+package cachex
+
+import (
+	"sync"
+	"time"
+)
+
+// Backend 分片内条目的存储方式
+type Backend int
+
+const (
+	// BackendMap 读写锁保护的 map，读取需要获取分片的读锁
+	BackendMap Backend = iota
+	// BackendSyncMap 在读写锁保护的 map 之外，把每个条目的只读副本发布到 sync.Map 中，读取不再加锁
+	// 每次写入需要额外复制一次条目，适合读远多于写、读锁的竞争成为瓶颈的场景
+	// 限制了 MaxEntries 或 MaxCost 时读取需要更新访问顺序，该选项不生效
+	BackendSyncMap
+)
+
+func newReads[K comparable, V any](c *BaseCache[K, V]) *sync.Map {
+	if c.opts.Backend != BackendSyncMap || c.bounded() {
+		return nil
+	}
+	return new(sync.Map)
+}
+
+// publish 发布条目当前状态的副本，条目在锁内被修改后需要调用，副本发布后不再修改
+func (s *cacheShard[K, V]) publish(item *cacheItemWrapper[K, V]) {
+	if s.reads == nil {
+		return
+	}
+	cp := *item
+	s.reads.Store(item.key, &cp)
+}
+
+// readItem 不修改数据地读取条目的副本，expired 表示条目已过期需要删除
+func (s *cacheShard[K, V]) readItem(key K, now time.Time) (item cacheItemWrapper[K, V], ok, expired bool) {
+	if s.reads != nil {
+		if v, found := s.reads.Load(key); found {
+			p := v.(*cacheItemWrapper[K, V])
+			if expired = p.expired(now); !expired {
+				item, ok = *p, true
+			}
+		}
+		return item, ok, expired
+	}
+	s.mu.RLock()
+	if p := s.cache[key]; p != nil {
+		if expired = p.expired(now); !expired {
+			item, ok = *p, true
+		}
+	}
+	s.mu.RUnlock()
+	return item, ok, expired
+}
@@ -0,0 +1,70 @@
+package cachex
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackendSyncMap(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Backend: BackendSyncMap, Shards: 4})
+	defer cache.Close()
+	if cache.shard("a").reads == nil {
+		t.Fatalf("Expected sync.Map backend to be enabled")
+	}
+
+	cache.Set("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+	Increment(cache, "a", 2)
+	if v, _ := cache.Get("a"); v != 3 {
+		t.Errorf("Expected increment to be visible, got %v", v)
+	}
+	cache.SetExpire("b", 2, time.Minute)
+	cache.Touch("b", 5*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected touched key to expire")
+	}
+	cache.Del("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected a to be deleted")
+	}
+	cache.Set("c", 3)
+	cache.Clear()
+	if _, ok := cache.Get("c"); ok {
+		t.Errorf("Expected c to be cleared")
+	}
+
+	bounded := NewBaseCache(OnceCacheOption[string, int]{Backend: BackendSyncMap, MaxEntries: 10})
+	defer bounded.Close()
+	if bounded.shard("a").reads != nil {
+		t.Errorf("Expected sync.Map backend to be disabled for bounded caches")
+	}
+}
+
+func TestBackendSyncMapConcurrent(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Backend: BackendSyncMap, Shards: 4})
+	defer cache.Close()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprint(i % 16)
+				switch i % 4 {
+				case 0:
+					cache.Set(key, i)
+				case 1:
+					cache.Touch(key, time.Minute)
+				default:
+					cache.Get(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Backoff 加载失败后的退避策略，连续失败时等待时间按 Multiplier 倍增长，直到 Max
+type Backoff struct {
+	// Initial 第一次失败后的等待时间，小于等于0时不启用退避
+	Initial time.Duration
+	// Max 等待时间的上限，小于等于0时不设上限
+	Max time.Duration
+	// Multiplier 每次连续失败后等待时间的倍数，小于等于1时使用2
+	Multiplier float64
+}
+
+// delay 返回第 failures 次连续失败后的等待时间
+func (b Backoff) delay(failures int) time.Duration {
+	m := b.Multiplier
+	if m <= 1 {
+		m = 2
+	}
+	d := float64(b.Initial)
+	for i := 1; i < failures; i++ {
+		d *= m
+		if b.Max > 0 && d >= float64(b.Max) {
+			return b.Max
+		}
+	}
+	return time.Duration(d)
+}
+
+// loadFailure 单个键的连续加载失败
+type loadFailure struct {
+	err   error
+	count int
+	until time.Time
+}
+
+// 以下方法均需在持有写锁的情况下调用
+
+// failure 返回退避期内缓存的加载错误，不在退避期内时返回 nil
+func (s *cacheShard[K, V]) failure(key K, now time.Time) error {
+	f, ok := s.failures[key]
+	if !ok || !now.Before(f.until) {
+		return nil
+	}
+	return f.err
+}
+
+// recordLoad 记录一次加载的结果，成功时清除失败记录，失败时延长退避期
+// 调用方取消或超时导致的失败不计入，也不会清除已有的失败记录
+func (s *cacheShard[K, V]) recordLoad(key K, err error, now time.Time) {
+	backoff := s.c.opts.ErrorBackoff
+	if backoff.Initial <= 0 {
+		return
+	}
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// 没有真正执行加载或结果无法说明数据源的状态，保持原有的退避状态
+		return
+	}
+	if err == nil {
+		delete(s.failures, key)
+		return
+	}
+	f, ok := s.failures[key]
+	if !ok {
+		f = &loadFailure{}
+		s.failures[key] = f
+	}
+	f.err = err
+	f.count++
+	f.until = now.Add(backoff.delay(f.count))
+}
+
+// sweepFailures 删除已经过了退避期的失败记录
+func (s *cacheShard[K, V]) sweepFailures(now time.Time) {
+	for key, f := range s.failures {
+		if !now.Before(f.until) {
+			delete(s.failures, key)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package cachex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/llyb120/gotool/cachex/clocktest"
+)
+
+func TestErrorBackoff(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:        clock,
+		ErrorBackoff: cachex.Backoff{Initial: time.Second, Max: 3 * time.Second},
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("backend down")
+	calls := 0
+	failing := func() (int, error) {
+		calls++
+		return 0, loadErr
+	}
+	// 连续失败后依次退避 1s、2s、3s（达到上限）
+	for i, wait := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		cache.GetOrSetFuncErr("a", failing)
+		if calls != i+1 {
+			t.Fatalf("Expected %d calls, got %d", i+1, calls)
+		}
+		clock.Advance(wait - time.Millisecond)
+		if _, err := cache.GetOrSetFuncErr("a", failing); !errors.Is(err, loadErr) || calls != i+1 {
+			t.Fatalf("Expected cached error during backoff %v, calls: %d, err: %v", wait, calls, err)
+		}
+		clock.Advance(time.Millisecond)
+	}
+
+	if v, err := cache.GetOrSetFuncErr("a", func() (int, error) { return 1, nil }); err != nil || v != 1 {
+		t.Errorf("Expected load after backoff, got %v, err: %v", v, err)
+	}
+	// 成功后重置，再次失败时从 Initial 开始
+	cache.Del("a")
+	cache.GetOrSetFuncErr("a", failing)
+	clock.Advance(time.Second)
+	cache.GetOrSetFuncErr("a", failing)
+	if calls != 6 {
+		t.Errorf("Expected backoff to reset after success, got %d calls", calls)
+	}
+}
+
+func TestErrorBackoffIgnoresCancel(t *testing.T) {
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		ErrorBackoff: cachex.Backoff{Initial: time.Hour},
+	})
+	defer cache.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+	// 调用方取消不计入失败，之后的加载照常执行
+	if v, err := cache.GetOrSetFuncErr("a", func() (int, error) { return 1, nil }); err != nil || v != 1 {
+		t.Errorf("Expected canceled load not to start a backoff, got %v, err: %v", v, err)
+	}
+}
+
+func TestErrorBackoffKeptAfterCancel(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:        clock,
+		ErrorBackoff: cachex.Backoff{Initial: time.Second},
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("backend down")
+	calls := 0
+	failing := func() (int, error) {
+		calls++
+		return 0, loadErr
+	}
+	cache.GetOrSetFuncErr("a", failing)
+	clock.Advance(time.Second)
+	cache.GetOrSetFuncErr("a", failing)
+	clock.Advance(2 * time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+
+	// 取消的加载不清除失败记录，第三次失败后退避 4s 而不是从 Initial 重新开始
+	cache.GetOrSetFuncErr("a", failing)
+	clock.Advance(4*time.Second - time.Millisecond)
+	if _, err := cache.GetOrSetFuncErr("a", failing); !errors.Is(err, loadErr) || calls != 3 {
+		t.Errorf("Expected backoff to survive a canceled load, calls: %d, err: %v", calls, err)
+	}
+}
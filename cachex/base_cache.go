@@ -2,124 +2,704 @@ package cachex
 
 import (
 	"context"
-	"sync"
+	"hash/maphash"
+	"sync/atomic"
 	"time"
 )
 
 // 一次性缓存，超过多久即会销毁
 
 type BaseCache[K comparable, V any] struct {
-	mu    sync.RWMutex
-	cache map[K]cacheItemWrapper[V]
-	opts  OnceCacheOption
+	shards []*cacheShard[K, V]
+	seed   maphash.Seed
+	opts   OnceCacheOption[K, V]
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed atomic.Bool
+	done   chan struct{}
+	stats  cacheStats
+	writer *storeWriter[K, V]
+	life   lifetime
+	events eventBus[K]
+	inval  invalidation
+	// expvars PublishExpvar 发布的变量，销毁时解除对缓存的引用
+	expvars expvarState
+	// breaker 加载熔断器，仅在 CircuitBreaker 启用时使用
+	breaker breaker
+	// sweepAt 自适应清理计划的下一次清理时间，wake 用于提前唤醒清理协程
+	sweepAt atomic.Int64
+	wake    chan struct{}
+	// version 最近一次写入分配的版本号
+	version atomic.Uint64
+	// maxEntries 当前的条目数上限，AutoScale 或 SetMaxEntries 会修改
+	maxEntries atomic.Int64
 }
 
-type OnceCacheOption struct {
+type OnceCacheOption[K comparable, V any] struct {
 	Expire           time.Duration
 	DefaultKeyExpire time.Duration
-	CheckInterval    time.Duration
+	// TTLFunc 根据值计算条目的有效期，例如使用上游响应自带的过期时间，设置后代替 DefaultKeyExpire
+	// 作用于 Set 以及所有没有显式指定有效期的写入和加载，返回值小于等于0时永不过期，在锁内调用，不能访问该缓存
+	TTLFunc       func(key K, value V) time.Duration
+	CheckInterval time.Duration
+	// MaxCheckInterval 大于 CheckInterval 时启用自适应清理，清理间隔在两者之间变化
+	// 每次清理后按最早到期的条目决定下一次的间隔，空闲的缓存以 MaxCheckInterval 低频唤醒
+	// 写入比计划更早到期的条目时会提前下一次清理
+	MaxCheckInterval time.Duration
 	Destroy          func()
+	// OnDestroy 缓存销毁时的回调，参数为销毁时仍未失效的条目，可用于将其写入持久化存储
+	// 在 Destroy 之前调用，两者可以同时设置
+	OnDestroy func(remaining map[K]V)
+	// MaxEntries 最大条目数，超出后淘汰最久未访问的键，小于等于0时不限制
+	MaxEntries int
+	// MaxCost 所有条目的成本上限，超出后按最久未访问的顺序淘汰，小于等于0时不限制
+	MaxCost int64
+	// Weigher 计算单个条目的成本，为空时每个条目的成本为1
+	Weigher func(key K, value V) int64
+	// MaxMemory 所有条目估算占用的内存上限，单位为字节，超出后按最久未访问的顺序淘汰
+	// 相当于以 EstimateSize 的结果为成本的 MaxCost，设置了 MaxCost 时忽略
+	MaxMemory int64
+	// OnEvict 条目被移除时的回调，在释放锁之后调用，可以安全地访问缓存
+	// 过期、手动删除、容量淘汰以及缓存销毁都会触发，替换已有的值不会触发
+	OnEvict func(key K, value V, reason EvictReason)
+	// OnLoad 每次加载函数执行完毕后的回调，可用于记录加载耗时
+	OnLoad func(key K, duration time.Duration, err error)
+	// Shards 分片数量，大于1时按键的哈希分散到多个分片，降低高并发下的锁竞争
+	// MaxEntries 和 MaxCost 会平均分配到每个分片，淘汰在分片内独立进行
+	Shards int
+	// Backend 分片内条目的存储方式，默认为 BackendMap
+	Backend Backend
+	// EvictionPolicy 超出 MaxEntries 或 MaxCost 时选择淘汰条目的算法，默认为 PolicyLRU
+	EvictionPolicy EvictionPolicy
+	// Loader 缓存自身的加载函数，用于后台刷新条目
+	Loader func(ctx context.Context, key K) (V, error)
+	// BulkLoader 支持批量加载的加载器，Preload 使用其 LoadAll 预热缓存
+	// 没有设置 Loader 时其 Load 方法同时用于后台刷新
+	BulkLoader Loader[K, V]
+	// PreloadBatch Preload 每批加载的键数量，默认为100
+	PreloadBatch int
+	// PreloadParallelism Preload 同时进行的批次数量，默认为4
+	PreloadParallelism int
+	// Invalidator 跨进程的失效通知，本地写入或删除键后通知其他副本删除各自的本地副本
+	// 通知由后台协程异步发布，同时排队的通知会合并为一次发布，缓存关闭时会发完队列中剩余的通知
+	Invalidator Invalidator
+	// InvalidateQueue 等待发布的失效通知的队列长度，队列满时写入阻塞等待，默认为1024
+	InvalidateQueue int
+	// InvalidateTimeout 单次发布失效通知的超时时间，默认为5秒
+	InvalidateTimeout time.Duration
+	// OnInvalidateError 发布或订阅失效通知失败时的回调
+	OnInvalidateError func(err error)
+	// WarmupFile 创建缓存时从该文件载入条目，格式与 WarmFrom 相同，载入完成后构造函数才返回
+	WarmupFile string
+	// OnWarmupError 载入 WarmupFile 失败时的回调，失败时缓存中可能只有部分条目
+	OnWarmupError func(err error)
+	// EventBuffer Events 通道的缓冲区大小，默认为1024
+	EventBuffer int
+	// StaleWhileRevalidate 条目过期后仍可返回旧值的时长，期间访问会触发 Loader 在后台刷新
+	// 需要同时设置 Loader，刷新后的条目使用 DefaultKeyExpire
+	StaleWhileRevalidate time.Duration
+	// RefreshAfter 条目写入超过该时长后被访问时，通过 Loader 在后台提前刷新，访问者不会被阻塞
+	// 需要同时设置 Loader，通常小于 DefaultKeyExpire，使热点条目始终不会过期
+	RefreshAfter time.Duration
+	// NegativeTTL 加载函数返回 ErrNotFound 时缓存这次未命中的时长，期间不会再次调用加载函数
+	// 小于等于0时不缓存未命中，SetNegative 写入的记录此时永不过期
+	NegativeTTL time.Duration
+	// ExpireJitter 写入时将键的有效期随机上下浮动的比例，例如0.1表示±10%
+	// 避免同时写入的大量键在同一时刻过期并集中重新加载，小于等于0时不浮动
+	ExpireJitter float64
+	// Store 持久化存储，设置后 Set 和 Del 会同步写入存储，加载函数得到的值不会写入
+	Store Store
+	// WriteMode 写入 Store 的方式，默认为 WriteThrough
+	WriteMode WriteMode
+	// WriteQueue WriteBehind 模式下写入队列的长度，队列满时 Set 阻塞等待，默认为1024
+	WriteQueue int
+	// StoreTimeout 单次写入或删除 Store 的超时时间，默认为5秒，超时按写入失败处理
+	// WriteThrough 模式下写入在分片锁内同步进行，以保证同一个键写入 Store 的顺序与缓存一致、写入失败时缓存保持不变，
+	// 期间同一分片的读写都需要等待，因此超时时间应小于可接受的最长等待
+	StoreTimeout time.Duration
+	// StoreKey 将键转换为 Store 和 Invalidator 中使用的字符串，为空时使用 fmt.Sprint
+	StoreKey func(key K) string
+	// ParseKey 将 Invalidator 收到的字符串还原为键，为空时支持字符串和整数类型的键
+	ParseKey func(s string) (K, bool)
+	// Codec 值写入 Store 时的编码方式，为空时使用 JSONCodec
+	Codec Codec
+	// OnStoreError 写入 Store 失败时的回调
+	OnStoreError func(key K, err error)
+	// Clock 判断过期以及后台清理使用的时间来源，为空时使用系统时间
+	// 缓存整体的生命周期 Expire 始终按系统时间计算
+	Clock Clock
+	// ErrorBackoff 加载失败后的退避策略，退避期内 GetOrSetFunc 系列方法直接返回上次的错误而不调用加载函数
+	// 连续失败时退避期按策略增长，加载成功后重置，为零值时不启用
+	ErrorBackoff Backoff
+	// CircuitBreaker 加载函数的熔断策略，按整个缓存的连续失败次数计算，Group 中即按分组计算
+	// 打开期间不调用加载函数，GetOrSetFunc 系列方法返回 ErrCircuitOpen
+	// 启用时过期条目的旧值会按 KeepStale 保留，打开期间返回旧值，不需要同时开启 StaleWhileRevalidate
+	CircuitBreaker CircuitBreaker
+	// AutoScale 按命中率和容量淘汰的情况在范围内自动调整 MaxEntries，需要同时设置 MaxEntries，为零值时不启用
+	AutoScale AutoScale
+	// CloneOnGet 返回值之前调用的复制函数，用于保护切片、map、指针等可变的缓存值不被调用方修改
+	// 作用于所有读取方法的返回值，写入和加载时不复制，为空时直接返回缓存中的值
+	CloneOnGet func(value V) V
+	// Equal CompareAndSwap 比较新旧值的方式，为空时使用 reflect.DeepEqual
+	Equal func(a, b V) bool
 }
 
-func NewBaseCache[K comparable, V any](opts OnceCacheOption) *BaseCache[K, V] {
+func NewBaseCache[K comparable, V any](opts OnceCacheOption[K, V]) *BaseCache[K, V] {
+	return NewBaseCacheCtx(context.Background(), opts)
+}
+
+// NewBaseCacheCtx 创建生命周期跟随 ctx 的缓存，ctx 结束时缓存被销毁并执行 Destroy
+// 同时设置了 Expire 时，以两者中先到的为准
+func NewBaseCacheCtx[K comparable, V any](ctx context.Context, opts OnceCacheOption[K, V]) *BaseCache[K, V] {
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+	}
+	if opts.Loader == nil && opts.BulkLoader != nil {
+		opts.Loader = opts.BulkLoader.Load
+	}
+	if opts.MaxMemory > 0 && opts.MaxCost <= 0 {
+		opts.MaxCost = opts.MaxMemory
+		if opts.Weigher == nil {
+			opts.Weigher = func(key K, value V) int64 {
+				return EstimateSize(key) + EstimateSize(value) + entryOverhead
+			}
+		}
+	}
 	cache := &BaseCache[K, V]{
-		opts:  opts,
-		cache: make(map[K]cacheItemWrapper[V]),
+		seed: maphash.MakeSeed(),
+		opts: opts,
+		done: make(chan struct{}),
+	}
+	n := opts.Shards
+	if n < 1 {
+		n = 1
+	}
+	maxEntries := ceilDiv(int64(opts.MaxEntries), int64(n))
+	maxCost := ceilDiv(opts.MaxCost, int64(n))
+	cache.shards = make([]*cacheShard[K, V], n)
+	for i := range cache.shards {
+		cache.shards[i] = newCacheShard(cache, int(maxEntries), maxCost)
+	}
+	cache.maxEntries.Store(int64(opts.MaxEntries))
+	cache.ctx, cache.cancel = context.WithCancel(ctx)
+	if opts.Expire > 0 {
+		cache.life.start(opts.Expire, cache.cancel)
+	}
+	if opts.Store != nil {
+		cache.writer = newStoreWriter(cache)
+	}
+	if opts.Invalidator != nil {
+		cache.subscribe()
+	}
+	if opts.CheckInterval > 0 && opts.MaxCheckInterval > opts.CheckInterval {
+		cache.wake = make(chan struct{}, 1)
+	}
+	if opts.AutoScale.enabled() && opts.MaxEntries > 0 {
+		go cache.autoscale()
 	}
 	go cache.start()
+	if opts.WarmupFile != "" {
+		if err := cache.warmupFile(opts.WarmupFile); err != nil && opts.OnWarmupError != nil {
+			opts.OnWarmupError(err)
+		}
+	}
 	return cache
 }
 
-func (c *BaseCache[K, V]) start() {
-	if c.opts.Expire > 0 && c.opts.Destroy != nil {
-		defer c.opts.Destroy()
+func ceilDiv(total, n int64) int64 {
+	if total <= 0 {
+		return 0
 	}
+	return (total + n - 1) / n
+}
 
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if c.opts.Expire > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), c.opts.Expire)
-	} else {
-		ctx, cancel = context.WithCancel(context.Background())
-	}
-	defer cancel()
+func (c *BaseCache[K, V]) start() {
+	defer close(c.done)
+	defer c.cancel()
 
+	sweeping := make(chan struct{})
 	if c.opts.CheckInterval > 0 {
 		// 小于等于0的时候永不过期
 		go func() {
-			ticker := time.NewTicker(c.opts.CheckInterval)
+			defer close(sweeping)
+			ticker := c.opts.Clock.NewTicker(c.opts.CheckInterval)
 			defer ticker.Stop()
+			c.sweepAt.Store(c.now().Add(c.opts.CheckInterval).UnixNano())
 
 			for {
 				select {
-				case <-ctx.Done():
+				case <-c.ctx.Done():
 					return
-				case <-ticker.C:
-					func() {
-						c.mu.Lock()
-						// 执行检查操作
-						defer c.mu.Unlock()
-						mp := make(map[K]cacheItemWrapper[V])
-						now := time.Now()
-						for key, item := range c.cache {
-							if item.canExpire && item.expire.After(now) {
-								mp[key] = item
-							}
-						}
-						c.cache = mp
-					}()
+				case <-ticker.C():
+					// 执行检查操作
+					now := c.now()
+					for _, s := range c.shards {
+						s.lock()
+						s.sweep(now)
+						s.unlock()
+					}
+					if c.wake != nil {
+						c.resetSweep(ticker, now)
+					}
+				case <-c.wake:
+					// 写入了比计划更早到期的条目，提前下一次清理
+					c.resetSweep(ticker, c.now())
 				}
 			}
 		}()
+	} else {
+		close(sweeping)
+	}
+
+	<-c.ctx.Done()
+	// 等待清理协程退出，Close 返回后不再有后台协程访问缓存
+	<-sweeping
+
+	// 缓存生命周期结束，标记为不可用并释放所有条目
+	c.closed.Store(true)
+	c.life.stop()
+	c.inval.stop()
+	c.unpublishExpvar()
+	var remaining map[K]V
+	if c.opts.OnDestroy != nil {
+		remaining = make(map[K]V)
 	}
+	now := c.now()
+	for _, s := range c.shards {
+		s.lock()
+		for key, item := range s.cache {
+			if remaining != nil && !item.negative && !item.expired(now) {
+				remaining[key] = item.value
+			}
+		}
+		s.clear(EvictDestroyed)
+		s.unlock()
+	}
+	if c.writer != nil {
+		// 所有分片都已关闭，不会再有新的写入，等待队列中的写入完成
+		c.writer.flush()
+	}
+
+	var zero K
+	c.emit(EventDestroyed, zero)
+	if c.opts.OnDestroy != nil {
+		c.opts.OnDestroy(remaining)
+	}
+	if c.opts.Destroy != nil {
+		c.opts.Destroy()
+	}
+}
 
-	<-ctx.Done()
+// Close 提前销毁缓存，停止后台清理协程并执行 Destroy 回调
+// 关闭后的缓存不再保存任何数据，可重复调用
+func (c *BaseCache[K, V]) Close() {
+	c.cancel()
+	<-c.done
+}
+
+// Closed 判断缓存是否已经销毁
+func (c *BaseCache[K, V]) Closed() bool {
+	return c.closed.Load()
 }
 
 func (c *BaseCache[K, V]) Set(key K, value V) {
-	c.SetExpire(key, value, c.opts.DefaultKeyExpire)
+	c.SetExpire(key, value, c.defaultExpire(key, value))
 }
 
 func (c *BaseCache[K, V]) SetExpire(key K, value V, expire time.Duration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.cache[key] = cacheItemWrapper[V]{
-		value:     value,
-		expire:    time.Now().Add(expire),
-		canExpire: expire > 0,
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return
 	}
+	expire = c.jitter(expire)
+	if c.writer != nil && !c.writer.set(key, value, expire) {
+		return
+	}
+	s.setItem(key, c.newItem(key, value, expire))
+	s.invalidate(key)
 }
 
-func (c *BaseCache[K, V]) Get(key K) (V, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	item, ok := c.cache[key]
+// SetIfAbsent 键不存在或已失效时写入值并返回 true，否则保持原值并返回 false
+func (c *BaseCache[K, V]) SetIfAbsent(key K, value V) bool {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return false
+	}
+	if item := s.getItem(key, c.now()); item != nil && !item.negative {
+		return false
+	}
+	expire := c.jitter(c.defaultExpire(key, value))
+	if c.writer != nil && !c.writer.set(key, value, expire) {
+		return false
+	}
+	s.setItem(key, c.newItem(key, value, expire))
+	s.invalidate(key)
+	return true
+}
+
+// CompareAndSwap 键当前的值等于 old 时替换为 new 并返回 true，新值使用 DefaultKeyExpire
+// 值的比较方式由 Equal 决定，键不存在或已失效时返回 false
+func (c *BaseCache[K, V]) CompareAndSwap(key K, old, new V) bool {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return false
+	}
+	item := s.getItem(key, c.now())
+	if item == nil || item.negative || !c.equal(item.value, old) {
+		return false
+	}
+	expire := c.jitter(c.defaultExpire(key, new))
+	if c.writer != nil && !c.writer.set(key, new, expire) {
+		return false
+	}
+	s.setItem(key, c.newItem(key, new, expire))
+	s.invalidate(key)
+	return true
+}
+
+// GetAndSet 写入新值并返回旧值，loaded 表示写入前键是否存在，读取和写入在同一次加锁内完成
+func (c *BaseCache[K, V]) GetAndSet(key K, value V) (old V, loaded bool) {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return old, false
+	}
+	if item := s.getItem(key, c.now()); item != nil && !item.negative {
+		old, loaded = c.clone(item.value), true
+	}
+	expire := c.jitter(c.defaultExpire(key, value))
+	if c.writer != nil && !c.writer.set(key, value, expire) {
+		var zero V
+		return zero, false
+	}
+	s.setItem(key, c.newItem(key, value, expire))
+	s.invalidate(key)
+	return old, loaded
+}
+
+// GetAndDelete 删除键并返回删除前的值，键不存在时返回 false
+// 并发调用时只有一个调用方能拿到值，适合一次性令牌等只能领取一次的数据
+func (c *BaseCache[K, V]) GetAndDelete(key K) (V, bool) {
+	var zero V
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	item := s.getItem(key, c.now())
+	if item == nil || item.negative {
+		return zero, false
+	}
+	if c.writer != nil && !c.closed.Load() && !c.writer.del(key) {
+		return zero, false
+	}
+	value := item.value
+	s.removeItem(key, item, EvictDeleted)
+	s.invalidate(key)
+	return value, true
+}
+
+// GetWithTTL 获取键对应的值及其剩余的有效期
+// 永不过期的条目返回的剩余时间小于0，已过新鲜期但仍可返回旧值的条目返回0
+func (c *BaseCache[K, V]) GetWithTTL(key K) (V, time.Duration, bool) {
+	now := c.now()
+	item, ok := c.lookup(key, now)
+	c.recordAccess(key, ok)
+	if !ok {
+		return item.value, 0, false
+	}
+	if !item.canExpire {
+		return item.value, -1, true
+	}
+	ttl := item.expire.Sub(now)
+	if ttl < 0 {
+		ttl = 0
+	}
+	return item.value, ttl, true
+}
+
+// GetE 获取键对应的值，未命中时通过错误区分原因
+// 键从未缓存、已被删除或记录为数据不存在时返回 ErrNotFound，
+// 键缓存过值但已过期时返回 ErrExpired，调用方可以据此选择返回旧数据还是同步加载
+// 过期的条目会在读取或定期清理时删除，被清理之后再读取返回 ErrNotFound
+func (c *BaseCache[K, V]) GetE(key K) (V, error) {
+	now := c.now()
+	s := c.shard(key)
+	s.mu.RLock()
+	p := s.cache[key]
+	lapsed := p != nil && !p.negative && p.expired(now)
+	s.mu.RUnlock()
+	if lapsed {
+		s.lock()
+		s.delExpired(key, now)
+		s.unlock()
+		c.recordAccess(key, false)
+		var zero V
+		return zero, ErrExpired
+	}
+	item, ok := c.lookup(key, now)
+	c.recordAccess(key, ok)
 	if !ok {
-		return item.value, false
+		return item.value, ErrNotFound
+	}
+	return item.value, nil
+}
+
+// SetNegative 记录键对应的数据不存在，有效期为 NegativeTTL
+// 期间 Get 返回未命中，GetOrSetFunc 系列方法直接返回 ErrNotFound 而不调用加载函数
+func (c *BaseCache[K, V]) SetNegative(key K) {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return
 	}
-	return item.value, true
+	s.setItem(key, c.newNegativeItem(key))
+}
+
+// IsNegative 判断键是否被记录为数据不存在
+func (c *BaseCache[K, V]) IsNegative(key K) bool {
+	s := c.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item := s.cache[key]
+	return item != nil && item.negative && !item.expired(c.now())
+}
+
+// Touch 将键的过期时间推迟到 extend 之后，不修改值，可用于实现滑动过期
+// extend 小于等于0时条目永不过期，键不存在或已失效时返回 false
+func (c *BaseCache[K, V]) Touch(key K, extend time.Duration) bool {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	now := c.now()
+	item := s.getItem(key, now)
+	if item == nil || item.negative {
+		return false
+	}
+	expire := now.Add(extend)
+	deadline := expire
+	if c.revalidating() {
+		deadline = expire.Add(c.opts.StaleWhileRevalidate)
+	}
+	s.setExpire(item, expire, deadline, extend > 0)
+	return true
+}
+
+// Get 获取键对应的值，已过期的条目视为不存在并在访问时删除
+func (c *BaseCache[K, V]) Get(key K) (V, bool) {
+	value, ok := c.get(key)
+	c.recordAccess(key, ok)
+	return value, ok
+}
+
+func (c *BaseCache[K, V]) get(key K) (V, bool) {
+	item, ok := c.lookup(key, c.now())
+	return item.value, ok
+}
+
+// lookup 查找未失效的条目并返回其副本，副本可以在锁外安全读取
+// 需要刷新的条目会触发后台刷新，数据不存在的记录视为未命中
+//
+// 读取只持有分片的读锁，多个读取之间不会互相阻塞，以下情况才升级为写锁：
+// 条目已过期需要删除，或限制容量时条目不在访问顺序的队首需要移动
+// 加载只在写锁内登记和写入，读锁内从不修改数据
+func (c *BaseCache[K, V]) lookup(key K, now time.Time) (cacheItemWrapper[K, V], bool) {
+	s := c.shard(key)
+	var item cacheItemWrapper[K, V]
+	var ok bool
+	if c.bounded() {
+		// 已在队首的热点条目无需移动，只用读锁，PolicyClock 不需要移动条目，总是只用读锁
+		clock := c.opts.EvictionPolicy == PolicyClock
+		s.mu.RLock()
+		if p := s.cache[key]; p != nil && !p.expired(now) && (p.pinned || clock || s.lru.Front() == p.elem) {
+			item, ok = *p, true
+		}
+		s.mu.RUnlock()
+		if !ok {
+			s.lock()
+			if p := s.getItem(key, now); p != nil {
+				item, ok = *p, true
+			}
+			s.unlock()
+		}
+	} else {
+		var expired bool
+		item, ok, expired = s.readItem(key, now)
+		if expired {
+			s.lock()
+			s.delExpired(key, now)
+			s.unlock()
+		}
+	}
+	if !ok || item.negative {
+		return cacheItemWrapper[K, V]{}, false
+	}
+	item.access.record(now)
+	if c.needsRefresh(&item, now) {
+		c.revalidate(s, key)
+	}
+	item.value = c.clone(item.value)
+	return item, true
 }
 
 func (c *BaseCache[K, V]) Del(key K) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	delete(c.cache, key)
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.writer != nil && !c.closed.Load() && !c.writer.del(key) {
+		return
+	}
+	if item, ok := s.cache[key]; ok {
+		s.removeItem(key, item, EvictDeleted)
+	}
+	delete(s.stale, key)
+	s.invalidate(key)
 }
 
 func (c *BaseCache[K, V]) GetOrSetFunc(key K, fn func() V) V {
+	value, _ := c.GetOrSetFuncErr(key, func() (V, error) {
+		return fn(), nil
+	})
+	return value
+}
+
+// GetOrSetFuncErr 获取键对应的值，不存在时调用 fn 加载
+// 只有 fn 成功时才会写入缓存，失败时直接返回错误
+// 设置了 NegativeTTL 时 fn 返回的 ErrNotFound 会被缓存，期间直接返回 ErrNotFound
+func (c *BaseCache[K, V]) GetOrSetFuncErr(key K, fn func() (V, error)) (V, error) {
+	return c.GetOrSetFuncCtx(context.Background(), key, func(ctx context.Context) (V, error) {
+		return fn()
+	})
+}
+
+// GetOrSetFuncCtx 与 GetOrSetFuncErr 相同，但加载过程受 ctx 控制
+// 同一个键并发未命中时只会执行一次 fn，其余调用等待并共享其结果
+// ctx 被取消或超时后加载结果不会写入缓存，并返回 ctx 的错误
+func (c *BaseCache[K, V]) GetOrSetFuncCtx(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
 	value, ok := c.Get(key)
-	if !ok {
-		if value, ok = c.Get(key); ok {
-			return value
+	if ok {
+		return value, nil
+	}
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	s := c.shard(key)
+	s.lock()
+	if item := s.getItem(key, c.now()); item != nil {
+		value, negative := item.value, item.negative
+		s.unlock()
+		if negative {
+			return zero, ErrNotFound
 		}
-		c.mu.Lock()
-		defer c.mu.Unlock()
-		value = fn()
-		c.cache[key] = cacheItemWrapper[V]{
-			value:  value,
-			expire: time.Now().Add(c.opts.DefaultKeyExpire),
+		return c.clone(value), nil
+	}
+	if err := s.failure(key, c.now()); err != nil {
+		s.unlock()
+		return zero, err
+	}
+	if call, ok := s.inflight[key]; ok {
+		s.unlock()
+		value, err := call.wait(ctx)
+		if stale, ok := c.staleOnOpen(s, key, err); ok {
+			return stale, nil
 		}
-		return value
+		if err != nil {
+			return value, err
+		}
+		return c.clone(value), nil
+	}
+	call := newFlightCall[V]()
+	s.inflight[key] = call
+	s.unlock()
+
+	c.load(ctx, s, key, call, fn)
+	if stale, ok := c.staleOnOpen(s, key, call.err); ok {
+		return stale, nil
+	}
+	if call.err != nil {
+		return call.value, call.err
+	}
+	return c.clone(call.value), nil
+}
+
+// snapshotItems 同时持有所有分片的读锁，复制出同一时刻未失效的条目
+func (c *BaseCache[K, V]) snapshotItems() []cacheItemWrapper[K, V] {
+	for _, s := range c.shards {
+		s.mu.RLock()
+	}
+	now := c.now()
+	var items []cacheItemWrapper[K, V]
+	for _, s := range c.shards {
+		for _, item := range s.cache {
+			if !item.negative && !item.expired(now) {
+				items = append(items, *item)
+			}
+		}
+	}
+	for _, s := range c.shards {
+		s.mu.RUnlock()
+	}
+	for i := range items {
+		items[i].value = c.clone(items[i].value)
+	}
+	return items
+}
+
+// Keys 返回所有未失效的键，顺序不固定
+func (c *BaseCache[K, V]) Keys() []K {
+	items := c.snapshotItems()
+	keys := make([]K, len(items))
+	for i, item := range items {
+		keys[i] = item.key
+	}
+	return keys
+}
+
+// Range 遍历所有未失效的条目，fn 返回 false 时停止
+// 遍历的是同一时刻的快照，fn 中可以安全地读写缓存，不会影响本次遍历
+func (c *BaseCache[K, V]) Range(fn func(key K, value V) bool) {
+	for _, item := range c.snapshotItems() {
+		if !fn(item.key, item.value) {
+			return
+		}
+	}
+}
+
+// Len 返回未失效的条目数量
+func (c *BaseCache[K, V]) Len() int {
+	now := c.now()
+	n := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for _, item := range s.cache {
+			if !item.negative && !item.expired(now) {
+				n++
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Clear 删除所有条目，缓存和后台清理协程继续可用，每个条目都会以 EvictDeleted 通知 OnEvict
+// 正在进行的加载不受影响，Store 中的数据也不会被删除
+func (c *BaseCache[K, V]) Clear() {
+	c.clearLocal()
+	c.publish(Invalidation{All: true})
+}
+
+func (c *BaseCache[K, V]) clearLocal() {
+	for _, s := range c.shards {
+		s.lock()
+		s.clear(EvictDeleted)
+		s.unlock()
 	}
-	return value
 }
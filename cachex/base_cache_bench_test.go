@@ -0,0 +1,161 @@
+package cachex
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func benchmarkBaseCacheParallel(b *testing.B, shards int) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Shards:     shards,
+		MaxEntries: 10000,
+	})
+	defer cache.Close()
+
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		cache.Set(keys[i], i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if i%10 == 0 {
+				cache.Set(key, i)
+			} else {
+				cache.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+// 单个分片，所有操作竞争同一把锁
+func BenchmarkBaseCacheSingleShard(b *testing.B) {
+	benchmarkBaseCacheParallel(b, 1)
+}
+
+// 256 个分片，锁竞争分散到各个分片
+func BenchmarkBaseCacheSharded(b *testing.B) {
+	benchmarkBaseCacheParallel(b, 256)
+}
+
+// benchmarkBaseCacheRead 只读的热点路径，opts 决定是否限制容量以及分片数量
+func benchmarkBaseCacheRead(b *testing.B, opts OnceCacheOption[string, int], keys int) {
+	cache := NewBaseCache(opts)
+	defer cache.Close()
+
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+		cache.Set(names[i], i)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.Get(names[i%len(names)])
+			i++
+		}
+	})
+}
+
+// 不限制容量，读取只持有读锁
+func BenchmarkBaseCacheReadUnbounded(b *testing.B) {
+	benchmarkBaseCacheRead(b, OnceCacheOption[string, int]{}, 1024)
+}
+
+// 限制容量的单个热点键，条目始终在队首，读取只持有读锁
+func BenchmarkBaseCacheReadBoundedHotKey(b *testing.B) {
+	benchmarkBaseCacheRead(b, OnceCacheOption[string, int]{MaxEntries: 10000}, 1)
+}
+
+// 限制容量的多个键，读取需要移动访问顺序
+func BenchmarkBaseCacheReadBounded(b *testing.B) {
+	benchmarkBaseCacheRead(b, OnceCacheOption[string, int]{MaxEntries: 10000}, 1024)
+}
+
+// 限制容量并分片，移动访问顺序的写锁分散到各个分片
+func BenchmarkBaseCacheReadBoundedSharded(b *testing.B) {
+	benchmarkBaseCacheRead(b, OnceCacheOption[string, int]{MaxEntries: 10000, Shards: 64}, 1024)
+}
+
+// 读多写少的负载下比较两种存储方式，分别使用 1、8、64 个协程
+func BenchmarkBackend(b *testing.B) {
+	backends := []struct {
+		name    string
+		backend Backend
+	}{{"Map", BackendMap}, {"SyncMap", BackendSyncMap}}
+	for _, bk := range backends {
+		for _, goroutines := range []int{1, 8, 64} {
+			b.Run(fmt.Sprintf("%s/%d", bk.name, goroutines), func(b *testing.B) {
+				benchmarkBackend(b, bk.backend, goroutines)
+			})
+		}
+	}
+}
+
+func benchmarkBackend(b *testing.B, backend Backend, goroutines int) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Shards: 16, Backend: backend})
+	defer cache.Close()
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		cache.Set(keys[i], i)
+	}
+
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				key := keys[i%len(keys)]
+				if i%100 == 0 {
+					cache.Set(key, i)
+				} else {
+					cache.Get(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+// 限制容量时比较 LRU 与 CLOCK 的读取开销，CLOCK 命中时不需要写锁
+func BenchmarkEvictionPolicy(b *testing.B) {
+	policies := []struct {
+		name   string
+		policy EvictionPolicy
+	}{{"LRU", PolicyLRU}, {"Clock", PolicyClock}}
+	for _, p := range policies {
+		b.Run(p.name, func(b *testing.B) {
+			cache := NewBaseCache(OnceCacheOption[string, int]{
+				Shards:         16,
+				MaxEntries:     10000,
+				EvictionPolicy: p.policy,
+			})
+			defer cache.Close()
+			keys := make([]string, 1024)
+			for i := range keys {
+				keys[i] = strconv.Itoa(i)
+				cache.Set(keys[i], i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cache.Get(keys[i%len(keys)])
+					i++
+				}
+			})
+		})
+	}
+}
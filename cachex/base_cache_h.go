@@ -0,0 +1,286 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// flightCall 表示一次正在进行的加载，等待者共享同一个结果
+type flightCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newFlightCall[V any]() *flightCall[V] {
+	return &flightCall[V]{done: make(chan struct{})}
+}
+
+// wait 等待加载完成，ctx 结束时提前返回
+func (f *flightCall[V]) wait(ctx context.Context) (V, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// load 在锁外执行加载函数，成功后写入缓存并唤醒所有等待者
+func (c *BaseCache[K, V]) load(ctx context.Context, s *cacheShard[K, V], key K, call *flightCall[V], fn func(ctx context.Context) (V, error)) {
+	breaking := c.opts.CircuitBreaker.Threshold > 0
+	if breaking && !c.breaker.allow(c.opts.CircuitBreaker, c.now()) {
+		call.err = ErrCircuitOpen
+		c.finishLoad(s, key, call, nil)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			c.stats.loadFailures.Add(1)
+			call.err = fmt.Errorf("cache loader panic: %v", r)
+			if breaking {
+				c.breaker.record(c.opts.CircuitBreaker, loadFailed, c.now())
+			}
+			c.finishLoad(s, key, call, nil)
+			panic(r)
+		}
+	}()
+
+	c.stats.loads.Add(1)
+	start := time.Now()
+	call.value, call.err = fn(ctx)
+	if call.err == nil {
+		call.err = ctx.Err()
+	}
+	if call.err != nil {
+		c.stats.loadFailures.Add(1)
+		var zero V
+		call.value = zero
+	}
+	if c.opts.OnLoad != nil {
+		c.opts.OnLoad(key, time.Since(start), call.err)
+	}
+	if breaking {
+		c.breaker.record(c.opts.CircuitBreaker, outcomeOf(call.err), c.now())
+	}
+	var item *cacheItemWrapper[K, V]
+	if call.err == nil {
+		item = c.newItem(key, call.value, c.jitter(c.defaultExpire(key, call.value)))
+	} else if c.opts.NegativeTTL > 0 && errors.Is(call.err, ErrNotFound) {
+		item = c.newNegativeItem(key)
+	}
+	c.finishLoad(s, key, call, item)
+}
+
+// finishLoad 写入加载得到的条目并唤醒等待者，item 为空时不写入
+func (c *BaseCache[K, V]) finishLoad(s *cacheShard[K, V], key K, call *flightCall[V], item *cacheItemWrapper[K, V]) {
+	s.lock()
+	if item != nil && !c.closed.Load() {
+		s.setItem(key, item)
+	}
+	s.recordLoad(key, call.err, c.now())
+	delete(s.inflight, key)
+	s.unlock()
+	close(call.done)
+}
+
+// notify 回调 OnEvict 并发布失效通知，必须在释放分片锁之后调用
+func (c *BaseCache[K, V]) notify(evicted []evictedEntry[K, V], invalid []string) {
+	for _, e := range evicted {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+	if len(invalid) > 0 {
+		c.publish(Invalidation{Keys: invalid})
+	}
+}
+
+// revalidate 在后台通过 Loader 刷新已过新鲜期的条目，刷新期间继续返回旧值
+// 同一个键同时只会有一个刷新任务，刷新失败时保留旧值直到其失效
+func (c *BaseCache[K, V]) revalidate(s *cacheShard[K, V], key K) {
+	s.lock()
+	if _, ok := s.inflight[key]; ok || c.closed.Load() {
+		s.unlock()
+		return
+	}
+	call := newFlightCall[V]()
+	s.inflight[key] = call
+	s.unlock()
+
+	go func() {
+		// 后台刷新的 panic 已经记录为加载错误，不再向上传播
+		defer func() { recover() }()
+		c.load(c.ctx, s, key, call, func(ctx context.Context) (V, error) {
+			return c.opts.Loader(ctx, key)
+		})
+	}()
+}
+
+// nextSweep 自适应清理的下一次间隔，即距离最早到期条目的时间，限制在 CheckInterval 和 MaxCheckInterval 之间
+func (c *BaseCache[K, V]) nextSweep(now time.Time) time.Duration {
+	next := c.opts.MaxCheckInterval
+	for _, s := range c.shards {
+		s.mu.RLock()
+		if len(s.expiry) > 0 {
+			if d := s.expiry[0].deadline.Sub(now); d < next {
+				next = d
+			}
+		}
+		s.mu.RUnlock()
+	}
+	if next < c.opts.CheckInterval {
+		next = c.opts.CheckInterval
+	}
+	return next
+}
+
+// resetSweep 按最早到期的条目重新安排下一次清理
+func (c *BaseCache[K, V]) resetSweep(ticker Ticker, now time.Time) {
+	d := c.nextSweep(now)
+	ticker.Reset(d)
+	c.sweepAt.Store(now.Add(d).UnixNano())
+}
+
+// nudgeSweep 条目的删除时间早于计划的下一次清理时唤醒清理协程
+func (c *BaseCache[K, V]) nudgeSweep(deadline time.Time) {
+	if c.wake == nil || deadline.UnixNano() >= c.sweepAt.Load() {
+		return
+	}
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newItem 创建条目，开启 StaleWhileRevalidate 时条目在过期后还会保留一段时间
+// expire 由调用方先经过 jitter，写入 Store 的有效期与内存中的保持一致
+func (c *BaseCache[K, V]) newItem(key K, value V, expire time.Duration) *cacheItemWrapper[K, V] {
+	item := newCacheItem(key, value, expire, c.now())
+	if c.revalidating() {
+		item.deadline = item.expire.Add(c.opts.StaleWhileRevalidate)
+	}
+	return item
+}
+
+// defaultExpire 没有显式指定有效期的写入使用的有效期
+func (c *BaseCache[K, V]) defaultExpire(key K, value V) time.Duration {
+	if c.opts.TTLFunc != nil {
+		return c.opts.TTLFunc(key, value)
+	}
+	return c.opts.DefaultKeyExpire
+}
+
+// jitter 按 ExpireJitter 随机调整有效期，永不过期的键保持不变
+func (c *BaseCache[K, V]) jitter(expire time.Duration) time.Duration {
+	if expire <= 0 || c.opts.ExpireJitter <= 0 {
+		return expire
+	}
+	delta := time.Duration(float64(expire) * c.opts.ExpireJitter * (2*rand.Float64() - 1))
+	if expire+delta <= 0 {
+		// 浮动比例过大时至少保留1纳秒，避免变成永不过期
+		return 1
+	}
+	return expire + delta
+}
+
+// newNegativeItem 创建表示数据不存在的条目，有效期为 NegativeTTL
+func (c *BaseCache[K, V]) newNegativeItem(key K) *cacheItemWrapper[K, V] {
+	var zero V
+	item := newCacheItem(key, zero, c.jitter(c.opts.NegativeTTL), c.now())
+	item.negative = true
+	return item
+}
+
+// needsRefresh 条目是否需要在后台刷新
+func (c *BaseCache[K, V]) needsRefresh(item *cacheItemWrapper[K, V], now time.Time) bool {
+	if c.opts.Loader == nil {
+		return false
+	}
+	if c.opts.RefreshAfter > 0 && now.Sub(item.created) >= c.opts.RefreshAfter {
+		return true
+	}
+	// 只有开启 StaleWhileRevalidate 或使用 SetExpires 写入的条目才会在过期前变旧
+	return item.stale(now)
+}
+
+// revalidating 是否开启了过期后返回旧值并后台刷新
+func (c *BaseCache[K, V]) revalidating() bool {
+	return c.opts.StaleWhileRevalidate > 0 && c.opts.Loader != nil
+}
+
+// shard 返回键所在的分片
+func (c *BaseCache[K, V]) shard(key K) *cacheShard[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[hashKey(c.seed, key)%uint64(len(c.shards))]
+}
+
+func (c *BaseCache[K, V]) now() time.Time {
+	return c.opts.Clock.Now()
+}
+
+// bounded 是否限制了缓存容量，限制时需要维护访问顺序
+func (c *BaseCache[K, V]) bounded() bool {
+	return c.opts.MaxEntries > 0 || c.opts.MaxCost > 0
+}
+
+func (c *BaseCache[K, V]) clone(value V) V {
+	if c.opts.CloneOnGet != nil {
+		return c.opts.CloneOnGet(value)
+	}
+	return value
+}
+
+func (c *BaseCache[K, V]) equal(a, b V) bool {
+	if c.opts.Equal != nil {
+		return c.opts.Equal(a, b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (c *BaseCache[K, V]) weigh(key K, value V) int64 {
+	if c.opts.Weigher == nil {
+		return 1
+	}
+	return c.opts.Weigher(key, value)
+}
+
+// hashKey 计算键的哈希值，常见类型走快速路径，其余类型按格式化后的字符串计算
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uint32:
+		return mix64(uint64(k))
+	case GroupKey:
+		return mix64(maphash.String(seed, k.Namespace)) ^ maphash.String(seed, k.Key)
+	default:
+		return maphash.String(seed, fmt.Sprintf("%#v", k))
+	}
+}
+
+// mix64 打散整数的位分布，避免连续的整数集中在相邻分片
+func mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
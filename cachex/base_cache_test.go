@@ -0,0 +1,1091 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBaseCacheClose(t *testing.T) {
+	destroyed := 0
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Expire:        time.Hour,
+		CheckInterval: 10 * time.Millisecond,
+		Destroy: func() {
+			destroyed++
+		},
+	})
+	cache.Set("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+
+	cache.Close()
+	if !cache.Closed() {
+		t.Errorf("Expected cache to be closed")
+	}
+	if destroyed != 1 {
+		t.Errorf("Expected Destroy to be called once, got %d", destroyed)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected no value after Close")
+	}
+
+	// 关闭后写入无效
+	cache.Set("b", 2)
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected Set to be ignored after Close")
+	}
+	if v := cache.GetOrSetFunc("c", func() int { return 3 }); v != 3 {
+		t.Errorf("Expected GetOrSetFunc to return 3, got %d", v)
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Errorf("Expected GetOrSetFunc not to store after Close")
+	}
+
+	// 重复关闭不会再次触发 Destroy
+	cache.Close()
+	if destroyed != 1 {
+		t.Errorf("Expected Destroy to be called once, got %d", destroyed)
+	}
+}
+
+func TestBaseCacheExpireDestroy(t *testing.T) {
+	done := make(chan struct{})
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Expire: 20 * time.Millisecond,
+		Destroy: func() {
+			close(done)
+		},
+	})
+	cache.Set("a", 1)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Destroy to be called after Expire")
+	}
+	if !cache.Closed() {
+		t.Errorf("Expected cache to be closed after Expire")
+	}
+}
+
+func TestBaseCacheLazyExpire(t *testing.T) {
+	// CheckInterval 为 0 时不会启动清理协程，依赖访问时的过期检查
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		DefaultKeyExpire: 20 * time.Millisecond,
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, 0)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected 'a' to be expired")
+	}
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Errorf("Expected key without expire to be kept, got %v, ok: %v", v, ok)
+	}
+
+	cache.Set("c", 3)
+	time.Sleep(40 * time.Millisecond)
+	calls := 0
+	v := cache.GetOrSetFunc("c", func() int {
+		calls++
+		return 4
+	})
+	if v != 4 || calls != 1 {
+		t.Errorf("Expected expired 'c' to be reloaded, got %v, calls: %d", v, calls)
+	}
+}
+
+func TestBaseCacheSweepKeepsPermanentKeys(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		CheckInterval: 5 * time.Millisecond,
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	s := cache.shards[0]
+	s.mu.RLock()
+	_, hasA := s.cache["a"]
+	_, hasB := s.cache["b"]
+	s.mu.RUnlock()
+	if !hasA {
+		t.Errorf("Expected sweeper to keep key without expire")
+	}
+	if hasB {
+		t.Errorf("Expected sweeper to remove expired key")
+	}
+}
+
+func TestBaseCacheGetOrSetFuncErr(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	errLoad := errors.New("load failed")
+	v, err := cache.GetOrSetFuncErr("a", func() (int, error) {
+		return 1, errLoad
+	})
+	if !errors.Is(err, errLoad) || v != 0 {
+		t.Errorf("Expected load error and zero value, got %v, %v", v, err)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected failed load not to be cached")
+	}
+
+	v, err = cache.GetOrSetFuncErr("a", func() (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 2 {
+		t.Errorf("Expected 2, got %v, %v", v, err)
+	}
+	v, err = cache.GetOrSetFuncErr("a", func() (int, error) {
+		return 3, nil
+	})
+	if err != nil || v != 2 {
+		t.Errorf("Expected cached 2, got %v, %v", v, err)
+	}
+}
+
+func TestBaseCacheGetOrSetFuncCtx(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	// 加载过程中 ctx 被取消，结果不写入缓存
+	ctx, cancel := context.WithCancel(context.Background())
+	v, err := cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		cancel()
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) || v != 0 {
+		t.Errorf("Expected context.Canceled, got %v, %v", v, err)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected cancelled load not to be cached")
+	}
+
+	// 已经取消的 ctx 不会触发加载
+	called := false
+	_, err = cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		called = true
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) || called {
+		t.Errorf("Expected loader not to be called with cancelled ctx, err: %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, err = cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 2 {
+		t.Errorf("Expected 2, got %v, %v", v, err)
+	}
+	if v, ok := cache.Get("a"); !ok || v != 2 {
+		t.Errorf("Expected cached 2, got %v, ok: %v", v, ok)
+	}
+}
+
+func TestBaseCacheGetOrSetFuncSingleflight(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	results := make([]int, 100)
+	for i := 0; i < 100; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = cache.GetOrSetFunc("a", func() int {
+				calls.Add(1)
+				time.Sleep(20 * time.Millisecond)
+				return 42
+			})
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected loader to run once, got %d", n)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("Expected result %d to be 42, got %d", i, v)
+		}
+	}
+
+	// 加载期间其他键不受影响
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go cache.GetOrSetFunc("slow", func() int {
+		close(started)
+		<-release
+		return 1
+	})
+	<-started
+	cache.Set("b", 2)
+	if v, ok := cache.Get("b"); !ok || v != 2 {
+		t.Errorf("Expected 2, got %v, ok: %v", v, ok)
+	}
+	close(release)
+}
+
+func TestBaseCacheMaxEntries(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries: 2,
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	// 访问 a，使 b 成为最久未访问的键
+	cache.Get("a")
+	cache.Set("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected 'b' to be evicted")
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Errorf("Expected 3, got %v, ok: %v", v, ok)
+	}
+
+	// 更新已存在的键不会触发淘汰
+	cache.Set("a", 10)
+	s := cache.shards[0]
+	if len(s.cache) != 2 || s.lru.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d/%d", len(s.cache), s.lru.Len())
+	}
+
+	for i := 0; i < 100; i++ {
+		cache.GetOrSetFunc(string(rune('d'+i)), func() int { return i })
+	}
+	if len(s.cache) != 2 || s.lru.Len() != 2 {
+		t.Errorf("Expected 2 entries after churn, got %d/%d", len(s.cache), s.lru.Len())
+	}
+}
+
+func TestBaseCacheMaxCost(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, []byte]{
+		MaxCost: 10,
+		Weigher: func(key string, value []byte) int64 {
+			return int64(len(value))
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("a", make([]byte, 4))
+	cache.Set("b", make([]byte, 4))
+	cache.Get("a")
+	// 总成本 12 超出预算，淘汰最久未访问的 b
+	cache.Set("c", make([]byte, 4))
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected 'b' to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Errorf("Expected 'a' to be kept")
+	}
+	if cache.shards[0].cost != 8 {
+		t.Errorf("Expected cost 8, got %d", cache.shards[0].cost)
+	}
+
+	// 替换条目时重新计算成本
+	cache.Set("a", make([]byte, 1))
+	if cache.shards[0].cost != 5 {
+		t.Errorf("Expected cost 5, got %d", cache.shards[0].cost)
+	}
+
+	// 超出整个预算的条目不会被保留
+	cache.Set("huge", make([]byte, 11))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("Expected oversized entry to be rejected")
+	}
+	if cache.shards[0].cost > 10 {
+		t.Errorf("Expected cost within budget, got %d", cache.shards[0].cost)
+	}
+}
+
+func TestBaseCacheOnEvict(t *testing.T) {
+	var mu sync.Mutex
+	reasons := make(map[string]EvictReason)
+	var cache *BaseCache[string, int]
+	cache = NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries: 2,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			// 回调中访问缓存不会死锁
+			cache.Get(key)
+			mu.Lock()
+			reasons[key] = reason
+			mu.Unlock()
+		},
+	})
+
+	cache.SetExpire("expired", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.Get("expired")
+
+	cache.Set("deleted", 2)
+	cache.Del("deleted")
+
+	cache.Set("a", 3)
+	cache.Set("a", 4) // 替换不触发回调
+	cache.Set("b", 5)
+	cache.Set("c", 6) // 淘汰 a
+
+	cache.Close() // 销毁 b 和 c
+
+	expected := map[string]EvictReason{
+		"expired": EvictExpired,
+		"deleted": EvictDeleted,
+		"a":       EvictCapacity,
+		"b":       EvictDestroyed,
+		"c":       EvictDestroyed,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reasons) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, reasons)
+	}
+	for key, reason := range expected {
+		if got, ok := reasons[key]; !ok || got != reason {
+			t.Errorf("Expected %s evicted by %v, got %v, ok: %v", key, reason, got, ok)
+		}
+	}
+}
+
+func TestBaseCacheStats(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries: 1,
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.Get("b")
+	cache.GetOrSetFunc("c", func() int { return 3 }) // 淘汰 a
+	cache.GetOrSetFuncErr("d", func() (int, error) {
+		return 0, errors.New("failed")
+	})
+
+	stats := cache.Stats()
+	expected := CacheStats{
+		Hits:         1,
+		Misses:       3,
+		Loads:        2,
+		LoadFailures: 1,
+		Evictions:    1,
+		Size:         1,
+	}
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+	if ratio := stats.HitRatio(); ratio != 0.25 {
+		t.Errorf("Expected hit ratio 0.25, got %v", ratio)
+	}
+
+	cache.ResetStats()
+	if stats := cache.Stats(); stats != (CacheStats{Size: 1}) {
+		t.Errorf("Expected reset stats, got %+v", stats)
+	}
+}
+
+func TestBaseCacheShards(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, int]{
+		Shards:     16,
+		MaxEntries: 160,
+	})
+	defer cache.Close()
+
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i*2)
+	}
+	for i := 0; i < 1000; i++ {
+		if v, ok := cache.Get(i); ok && v != i*2 {
+			t.Errorf("Expected %d, got %d", i*2, v)
+		}
+	}
+	used := 0
+	for _, s := range cache.shards {
+		if len(s.cache) > s.maxEntries {
+			t.Errorf("Expected shard to hold at most %d entries, got %d", s.maxEntries, len(s.cache))
+		}
+		if len(s.cache) > 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		t.Errorf("Expected keys to be spread across shards, got %d used", used)
+	}
+	if size := cache.Stats().Size; size == 0 || size > 160 {
+		t.Errorf("Expected size within (0, 160], got %d", size)
+	}
+
+	cache.Del(1)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected key 1 to be deleted")
+	}
+}
+
+func TestBaseCacheExpiryHeap(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, int]{})
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		// 偶数键很快过期，奇数键长期有效
+		if i%2 == 0 {
+			cache.SetExpire(i, i, time.Duration(i+1)*time.Millisecond)
+		} else {
+			cache.SetExpire(i, i, time.Hour)
+		}
+	}
+	cache.Set(1000, 1000)
+	// 替换和删除会同步移除堆中的旧条目
+	cache.SetExpire(2, 2, time.Hour)
+	cache.Del(3)
+
+	s := cache.shards[0]
+	s.lock()
+	if len(s.expiry) != 99 {
+		t.Errorf("Expected 99 entries in expiry heap, got %d", len(s.expiry))
+	}
+	s.unlock()
+
+	time.Sleep(150 * time.Millisecond)
+	s.lock()
+	s.sweep(time.Now())
+	for i, item := range s.expiry {
+		if item.heapIndex != i {
+			t.Errorf("Expected heap index %d, got %d", i, item.heapIndex)
+		}
+		if item.expired(time.Now()) {
+			t.Errorf("Expected no expired entry after sweep, got key %d", item.key)
+		}
+	}
+	// 49 个奇数键 + 键 2
+	if len(s.expiry) != 50 {
+		t.Errorf("Expected 50 entries in expiry heap, got %d", len(s.expiry))
+	}
+	// 加上永不过期的 1000
+	if len(s.cache) != 51 {
+		t.Errorf("Expected 51 entries, got %d", len(s.cache))
+	}
+	s.unlock()
+}
+
+func TestBaseCacheStaleWhileRevalidate(t *testing.T) {
+	var loads atomic.Int32
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		DefaultKeyExpire:     20 * time.Millisecond,
+		StaleWhileRevalidate: time.Hour,
+		Loader: func(ctx context.Context, key string) (int, error) {
+			n := loads.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return int(n) * 10, nil
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	time.Sleep(30 * time.Millisecond)
+
+	// 已过期，立即返回旧值并触发后台刷新
+	for i := 0; i < 10; i++ {
+		if v, ok := cache.Get("a"); !ok || v != 1 {
+			t.Errorf("Expected stale value 1, got %v, ok: %v", v, ok)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := loads.Load(); n != 1 {
+		t.Errorf("Expected a single background refresh, got %d", n)
+	}
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Errorf("Expected refreshed value 10, got %v, ok: %v", v, ok)
+	}
+}
+
+func TestBaseCacheRefreshAfter(t *testing.T) {
+	var loads atomic.Int32
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		DefaultKeyExpire: time.Hour,
+		RefreshAfter:     20 * time.Millisecond,
+		Loader: func(ctx context.Context, key string) (int, error) {
+			return int(loads.Add(1)) * 10, nil
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	if v, _ := cache.Get("a"); v != 1 || loads.Load() != 0 {
+		t.Errorf("Expected fresh value without refresh, got %v, loads: %d", v, loads.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected current value 1 while refreshing, got %v, ok: %v", v, ok)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if v, ok := cache.Get("a"); !ok || v != 10 {
+		t.Errorf("Expected refreshed value 10, got %v, ok: %v", v, ok)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("Expected 1 refresh, got %d", n)
+	}
+}
+
+func TestNegativeCache(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{NegativeTTL: 20 * time.Millisecond})
+	defer cache.Close()
+
+	var calls int
+	load := func() (int, error) {
+		calls++
+		return 0, fmt.Errorf("query user: %w", ErrNotFound)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetOrSetFuncErr("a", load); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected loader to be called once, got %d", calls)
+	}
+	if !cache.IsNegative("a") {
+		t.Errorf("Expected 'a' to be negative")
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected negative entry to be a miss")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if cache.IsNegative("a") {
+		t.Errorf("Expected negative entry to expire")
+	}
+	cache.GetOrSetFuncErr("a", load)
+	if calls != 2 {
+		t.Errorf("Expected loader to be called again after NegativeTTL, got %d", calls)
+	}
+
+	cache.SetNegative("b")
+	if v := cache.GetOrSetFunc("b", func() int { return 1 }); v != 0 {
+		t.Errorf("Expected zero value for negative entry, got %v", v)
+	}
+	cache.Set("b", 2)
+	if v, ok := cache.Get("b"); !ok || v != 2 || cache.IsNegative("b") {
+		t.Errorf("Expected Set to replace negative entry, got %v, ok: %v", v, ok)
+	}
+}
+
+func TestNegativeCacheDisabled(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	var calls int
+	for i := 0; i < 2; i++ {
+		cache.GetOrSetFuncErr("a", func() (int, error) {
+			calls++
+			return 0, ErrNotFound
+		})
+	}
+	if calls != 2 || cache.IsNegative("a") {
+		t.Errorf("Expected ErrNotFound not to be cached without NegativeTTL, calls: %d", calls)
+	}
+}
+
+func TestGetAndSet(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	if old, loaded := cache.GetAndSet("a", 1); loaded || old != 0 {
+		t.Errorf("Expected no previous value, got %v, loaded: %v", old, loaded)
+	}
+	if old, loaded := cache.GetAndSet("a", 2); !loaded || old != 1 {
+		t.Errorf("Expected previous value 1, got %v, loaded: %v", old, loaded)
+	}
+	if v, _ := cache.Get("a"); v != 2 {
+		t.Errorf("Expected 2, got %v", v)
+	}
+}
+
+func TestGetAndDelete(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	cache.Set("token", 1)
+
+	// 并发领取时只有一个调用方成功
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, ok := cache.GetAndDelete("token"); ok && v == 1 {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := claimed.Load(); n != 1 {
+		t.Errorf("Expected exactly one claim, got %d", n)
+	}
+	if _, ok := cache.Get("token"); ok {
+		t.Errorf("Expected token to be deleted")
+	}
+}
+
+func TestGetWithTTL(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, time.Minute)
+
+	if v, ttl, ok := cache.GetWithTTL("a"); !ok || v != 1 || ttl >= 0 {
+		t.Errorf("Expected negative ttl for key without expire, got %v, %v, ok: %v", v, ttl, ok)
+	}
+	if v, ttl, ok := cache.GetWithTTL("b"); !ok || v != 2 || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected ttl within a minute, got %v, %v, ok: %v", v, ttl, ok)
+	}
+	if _, _, ok := cache.GetWithTTL("c"); ok {
+		t.Errorf("Expected miss for 'c'")
+	}
+}
+
+func TestGetE(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{NegativeTTL: time.Minute})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, 5*time.Millisecond)
+	cache.SetNegative("c")
+
+	if v, err := cache.GetE("a"); err != nil || v != 1 {
+		t.Errorf("Expected 1, got %v, err: %v", v, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := cache.GetE("b"); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+	for _, key := range []string{"b", "c", "missing"} {
+		if _, err := cache.GetE(key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %q, got %v", key, err)
+		}
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 4 {
+		t.Errorf("Expected 1 hit and 4 misses, got %+v", stats)
+	}
+}
+
+func TestTTLFunc(t *testing.T) {
+	type response struct {
+		body   string
+		maxAge time.Duration
+	}
+	cache := NewBaseCache(OnceCacheOption[string, response]{
+		DefaultKeyExpire: time.Hour,
+		TTLFunc: func(key string, value response) time.Duration {
+			return value.maxAge
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("short", response{"a", time.Minute})
+	cache.Set("forever", response{"b", 0})
+	cache.GetOrSetFunc("loaded", func() response { return response{"c", 2 * time.Minute} })
+	cache.MGetOrLoad(context.Background(), []string{"batch"}, func(ctx context.Context, keys []string) (map[string]response, error) {
+		return map[string]response{"batch": {"d", 3 * time.Minute}}, nil
+	})
+
+	tests := []struct {
+		key string
+		ttl time.Duration
+	}{{"short", time.Minute}, {"loaded", 2 * time.Minute}, {"batch", 3 * time.Minute}}
+	for _, tt := range tests {
+		if _, ttl, ok := cache.GetWithTTL(tt.key); !ok || ttl <= tt.ttl-time.Second || ttl > tt.ttl {
+			t.Errorf("Expected ttl %v for %q, got %v, ok: %v", tt.ttl, tt.key, ttl, ok)
+		}
+	}
+	if _, ttl, ok := cache.GetWithTTL("forever"); !ok || ttl >= 0 {
+		t.Errorf("Expected zero TTLFunc result to never expire, got %v", ttl)
+	}
+	cache.SetExpire("explicit", response{"e", time.Minute}, time.Hour)
+	if _, ttl, _ := cache.GetWithTTL("explicit"); ttl <= time.Minute {
+		t.Errorf("Expected explicit expire to take precedence, got %v", ttl)
+	}
+}
+
+func TestTouch(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{CheckInterval: 5 * time.Millisecond})
+	defer cache.Close()
+	cache.SetExpire("a", 1, 30*time.Millisecond)
+	cache.SetExpire("b", 2, 30*time.Millisecond)
+
+	// 持续访问的键不会过期
+	for i := 0; i < 5; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if !cache.Touch("a", 30*time.Millisecond) {
+			t.Fatalf("Expected Touch to succeed")
+		}
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected touched key to stay, got %v, ok: %v", v, ok)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected untouched key to expire")
+	}
+	if cache.Touch("b", time.Minute) {
+		t.Errorf("Expected Touch on missing key to fail")
+	}
+
+	cache.Touch("a", 0)
+	if _, ttl, ok := cache.GetWithTTL("a"); !ok || ttl >= 0 {
+		t.Errorf("Expected key to never expire, got %v", ttl)
+	}
+	if n := len(cache.shards[0].expiry); n != 0 {
+		t.Errorf("Expected expiry heap to be empty, got %d", n)
+	}
+}
+
+func TestSetIfAbsent(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	var won atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if cache.SetIfAbsent("a", i) {
+				won.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if won.Load() != 1 {
+		t.Errorf("Expected exactly one writer to win, got %d", won.Load())
+	}
+
+	cache.SetExpire("b", 1, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if !cache.SetIfAbsent("b", 2) {
+		t.Errorf("Expected SetIfAbsent to replace expired entry")
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	cache.Set("a", 0)
+
+	// 并发自增，每次失败后重试
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, _ := cache.Get("a")
+				if cache.CompareAndSwap("a", v, v+1) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if v, _ := cache.Get("a"); v != 10 {
+		t.Errorf("Expected 10, got %d", v)
+	}
+	if cache.CompareAndSwap("missing", 0, 1) {
+		t.Errorf("Expected CompareAndSwap on missing key to fail")
+	}
+
+	// 不可比较的类型使用 reflect.DeepEqual 或 Equal
+	slices := NewBaseCache(OnceCacheOption[string, []int]{})
+	defer slices.Close()
+	slices.Set("a", []int{1, 2})
+	if !slices.CompareAndSwap("a", []int{1, 2}, []int{3}) {
+		t.Errorf("Expected DeepEqual comparison to match")
+	}
+	byLen := NewBaseCache(OnceCacheOption[string, []int]{
+		Equal: func(a, b []int) bool { return len(a) == len(b) },
+	})
+	defer byLen.Close()
+	byLen.Set("a", []int{1})
+	if !byLen.CompareAndSwap("a", []int{9}, []int{1, 2}) {
+		t.Errorf("Expected custom Equal to be used")
+	}
+}
+
+func TestKeysRange(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Shards: 4})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SetExpire("c", 3, 10*time.Millisecond)
+	cache.SetNegative("d")
+	time.Sleep(20 * time.Millisecond)
+
+	keys := cache.Keys()
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		t.Errorf("Expected live keys [a b], got %v", keys)
+	}
+
+	sum := 0
+	cache.Range(func(key string, value int) bool {
+		sum += value
+		// 遍历期间修改缓存不会死锁
+		cache.Del(key)
+		return true
+	})
+	if sum != 3 {
+		t.Errorf("Expected sum 3, got %d", sum)
+	}
+	if len(cache.Keys()) != 0 {
+		t.Errorf("Expected all keys to be deleted")
+	}
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	n := 0
+	cache.Range(func(key string, value int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Expected Range to stop early, got %d", n)
+	}
+}
+
+func TestLenClear(t *testing.T) {
+	var evicted atomic.Int32
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Shards:        4,
+		CheckInterval: 5 * time.Millisecond,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			if reason == EvictDeleted {
+				evicted.Add(1)
+			}
+		},
+	})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SetExpire("c", 3, time.Nanosecond)
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected 2 live entries, got %d", n)
+	}
+	// 等待后台清理移除已过期的 c，避免计入 Clear 的删除
+	time.Sleep(20 * time.Millisecond)
+	cache.Clear()
+	if n := cache.Len(); n != 0 || evicted.Load() != 2 {
+		t.Errorf("Expected empty cache after Clear, len: %d, evicted: %d", n, evicted.Load())
+	}
+
+	// 清空后缓存和后台清理仍然可用
+	cache.SetExpire("d", 4, 10*time.Millisecond)
+	if v, ok := cache.Get("d"); !ok || v != 4 || cache.Closed() {
+		t.Errorf("Expected cache to stay usable after Clear")
+	}
+	time.Sleep(30 * time.Millisecond)
+	// Stats().Size 不包含已过期的条目，直接检查分片中是否还留有该条目
+	s := cache.shard("d")
+	s.mu.RLock()
+	n := len(s.cache)
+	s.mu.RUnlock()
+	if n != 0 {
+		t.Errorf("Expected sweeper to remove expired entry, size: %d", n)
+	}
+}
+
+func TestExpireJitter(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, int]{ExpireJitter: 0.1})
+	defer cache.Close()
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		cache.SetExpire(i, i, time.Hour)
+		_, ttl, _ := cache.GetWithTTL(i)
+		if ttl < 54*time.Minute || ttl > 66*time.Minute {
+			t.Fatalf("Expected ttl within ±10%%, got %v", ttl)
+		}
+		seen[ttl.Truncate(time.Second)] = true
+	}
+	if len(seen) < 10 {
+		t.Errorf("Expected ttls to be spread out, got %d distinct values", len(seen))
+	}
+
+	cache.Set(-1, 0)
+	if _, ttl, _ := cache.GetWithTTL(-1); ttl >= 0 {
+		t.Errorf("Expected key without expire to stay permanent, got %v", ttl)
+	}
+}
+
+func TestExpireJitterStore(t *testing.T) {
+	store := &recordingStore{MemoryStore: NewMemoryStore()}
+	cache := NewBaseCache(OnceCacheOption[int, int]{ExpireJitter: 0.1, Store: store})
+	defer cache.Close()
+
+	// 写入 Store 的有效期与内存中浮动后的有效期相同
+	for i := 0; i < 20; i++ {
+		cache.SetExpire(i, i, time.Hour)
+		item := cache.shard(i).cache[i]
+		if want := item.expire.Sub(item.created); store.ttls[i] != want {
+			t.Fatalf("Expected store ttl %v to match jittered ttl %v", store.ttls[i], want)
+		}
+	}
+}
+
+func TestOnDestroy(t *testing.T) {
+	var remaining map[string]int
+	var order []string
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		OnDestroy: func(m map[string]int) {
+			remaining = m
+			order = append(order, "OnDestroy")
+		},
+		Destroy: func() {
+			order = append(order, "Destroy")
+		},
+	})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SetExpire("c", 3, time.Nanosecond)
+	cache.SetNegative("d")
+	time.Sleep(time.Millisecond)
+	cache.Close()
+
+	if !reflect.DeepEqual(remaining, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("Expected unexpired entries, got %v", remaining)
+	}
+	if !reflect.DeepEqual(order, []string{"OnDestroy", "Destroy"}) {
+		t.Errorf("Expected OnDestroy before Destroy, got %v", order)
+	}
+}
+
+func TestExtendLifetime(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Expire: 30 * time.Millisecond})
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	start, ok := cache.Deadline()
+	if !ok {
+		t.Fatalf("Expected cache to have a deadline")
+	}
+	for i := 0; i < 4; i++ {
+		time.Sleep(15 * time.Millisecond)
+		if !cache.ResetLifetime() {
+			t.Fatalf("Expected ResetLifetime to succeed")
+		}
+	}
+	if !cache.ExtendLifetime(30 * time.Millisecond) {
+		t.Fatalf("Expected ExtendLifetime to succeed")
+	}
+	if deadline, _ := cache.Deadline(); deadline.Sub(start) < 80*time.Millisecond {
+		t.Errorf("Expected deadline to be pushed back, moved by %v", deadline.Sub(start))
+	}
+	if _, ok := cache.Get("a"); !ok || cache.Closed() {
+		t.Errorf("Expected cache to stay alive")
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	if !cache.Closed() {
+		t.Errorf("Expected cache to die after its extended lifetime")
+	}
+	if cache.ExtendLifetime(time.Hour) {
+		t.Errorf("Expected ExtendLifetime on closed cache to fail")
+	}
+
+	forever := NewBaseCache(OnceCacheOption[string, int]{})
+	defer forever.Close()
+	if _, ok := forever.Deadline(); ok || forever.ExtendLifetime(time.Hour) {
+		t.Errorf("Expected cache without Expire to have no lifetime")
+	}
+}
+
+func TestNewBaseCacheCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	destroyed := make(chan struct{})
+	cache := NewBaseCacheCtx(ctx, OnceCacheOption[string, int]{
+		Destroy: func() {
+			close(destroyed)
+		},
+	})
+	cache.Set("a", 1)
+	if _, ok := cache.Get("a"); !ok {
+		t.Errorf("Expected cache to work before ctx is done")
+	}
+
+	cancel()
+	select {
+	case <-destroyed:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Destroy to be called when ctx is done")
+	}
+	if _, ok := cache.Get("a"); ok || !cache.Closed() {
+		t.Errorf("Expected cache to be destroyed with its ctx")
+	}
+}
+
+func TestCloneOnGet(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, []int]{
+		CloneOnGet: func(v []int) []int {
+			return append([]int(nil), v...)
+		},
+	})
+	defer cache.Close()
+	cache.Set("a", []int{1, 2, 3})
+
+	v, _ := cache.Get("a")
+	v[0] = 100
+	if v, _ := cache.Get("a"); v[0] != 1 {
+		t.Errorf("Expected cached value to be protected, got %v", v)
+	}
+	m := cache.MGet([]string{"a"})
+	m["a"][1] = 100
+	loaded, _ := cache.GetOrSetFuncErr("b", func() ([]int, error) {
+		return []int{4}, nil
+	})
+	loaded[0] = 100
+	cache.Range(func(key string, value []int) bool {
+		value[0] = 100
+		return true
+	})
+	if v, _ := cache.Get("a"); !reflect.DeepEqual(v, []int{1, 2, 3}) {
+		t.Errorf("Expected all reads to return copies, got %v", v)
+	}
+	if v, _ := cache.Get("b"); v[0] != 4 {
+		t.Errorf("Expected loaded value to be protected, got %v", v)
+	}
+}
+
+func TestStatsSize(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{NegativeTTL: time.Minute})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, 10*time.Millisecond)
+	cache.SetNegative("c")
+	time.Sleep(20 * time.Millisecond)
+	// 已过期但尚未清理的条目和 SetNegative 的占位都不计入
+	if size, n := cache.Stats().Size, cache.Len(); size != 1 || n != 1 {
+		t.Errorf("Expected Stats().Size to match Len, got size: %d, len: %d", size, n)
+	}
+}
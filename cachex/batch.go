@@ -0,0 +1,125 @@
+package cachex
+
+import (
+	"context"
+	"time"
+)
+
+// groupKeys 按分片对键分组，使每个分片在一次批量操作中只加锁一次
+func (c *BaseCache[K, V]) groupKeys(keys []K) map[*cacheShard[K, V]][]K {
+	groups := make(map[*cacheShard[K, V]][]K, len(c.shards))
+	for _, key := range keys {
+		s := c.shard(key)
+		groups[s] = append(groups[s], key)
+	}
+	return groups
+}
+
+// MGet 批量获取多个键，结果中只包含命中的键
+func (c *BaseCache[K, V]) MGet(keys []K) map[K]V {
+	result := make(map[K]V, len(keys))
+	now := c.now()
+	for s, group := range c.groupKeys(keys) {
+		var refresh []K
+		s.lock()
+		for _, key := range group {
+			item := s.getItem(key, now)
+			hit := item != nil && !item.negative
+			c.recordAccess(key, hit)
+			if !hit {
+				continue
+			}
+			item.access.record(now)
+			result[key] = c.clone(item.value)
+			if c.needsRefresh(item, now) {
+				refresh = append(refresh, key)
+			}
+		}
+		s.unlock()
+		for _, key := range refresh {
+			c.revalidate(s, key)
+		}
+	}
+	return result
+}
+
+// MSet 批量写入多个键，所有键使用相同的有效期，小于等于0时永不过期
+func (c *BaseCache[K, V]) MSet(values map[K]V, expire time.Duration) {
+	c.mset(values, func(K, V) time.Duration { return expire })
+}
+
+// mset 批量写入多个键，每个键的有效期由 expire 计算
+func (c *BaseCache[K, V]) mset(values map[K]V, expire func(key K, value V) time.Duration) {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	for s, group := range c.groupKeys(keys) {
+		s.lock()
+		if c.closed.Load() {
+			s.unlock()
+			return
+		}
+		for _, key := range group {
+			value := values[key]
+			ttl := c.jitter(expire(key, value))
+			if c.writer != nil && !c.writer.set(key, value, ttl) {
+				continue
+			}
+			s.setItem(key, c.newItem(key, value, ttl))
+			s.invalidate(key)
+		}
+		s.unlock()
+	}
+}
+
+// MDel 批量删除多个键
+func (c *BaseCache[K, V]) MDel(keys ...K) {
+	for s, group := range c.groupKeys(keys) {
+		s.lock()
+		for _, key := range group {
+			if c.writer != nil && !c.closed.Load() && !c.writer.del(key) {
+				continue
+			}
+			if item, ok := s.cache[key]; ok {
+				s.removeItem(key, item, EvictDeleted)
+			}
+			s.invalidate(key)
+		}
+		s.unlock()
+	}
+}
+
+// MGetOrLoad 批量获取多个键，未命中的键一次性交给 fn 加载，加载结果按 TTLFunc 或 DefaultKeyExpire 写入缓存
+// fn 返回的结果中缺少的键视为不存在，fn 失败时返回已命中的部分和错误
+// 与 GetOrSetFuncCtx 不同，并发的批量加载之间不会合并
+func (c *BaseCache[K, V]) MGetOrLoad(ctx context.Context, keys []K, fn func(ctx context.Context, missing []K) (map[K]V, error)) (map[K]V, error) {
+	result := c.MGet(keys)
+	var missing []K
+	for _, key := range keys {
+		if _, ok := result[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	c.stats.loads.Add(1)
+	loaded, err := fn(ctx, missing)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		c.stats.loadFailures.Add(1)
+		return result, err
+	}
+	c.mset(loaded, c.defaultExpire)
+	for key, value := range loaded {
+		result[key] = value
+	}
+	return result, nil
+}
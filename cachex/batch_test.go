@@ -0,0 +1,62 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, string]{Shards: 4})
+	defer cache.Close()
+
+	cache.MSet(map[int]string{1: "a", 2: "b", 3: "c"}, 0)
+	cache.MSet(map[int]string{4: "d"}, 10*time.Millisecond)
+	got := cache.MGet([]int{1, 2, 3, 4, 5})
+	want := map[int]string{1: "a", 2: "b", 3: "c", 4: "d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if stats := cache.Stats(); stats.Hits != 4 || stats.Misses != 1 {
+		t.Errorf("Expected 4 hits and 1 miss, got %+v", stats)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	cache.MDel(1, 2)
+	got = cache.MGet([]int{1, 2, 3, 4})
+	if !reflect.DeepEqual(got, map[int]string{3: "c"}) {
+		t.Errorf("Expected only 3 to remain, got %v", got)
+	}
+}
+
+func TestMGetOrLoad(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, string]{})
+	defer cache.Close()
+	cache.Set(1, "a")
+
+	var requested []int
+	load := func(ctx context.Context, missing []int) (map[int]string, error) {
+		requested = append(requested, missing...)
+		return map[int]string{2: "b"}, nil
+	}
+	got, err := cache.MGetOrLoad(context.Background(), []int{1, 2, 3}, load)
+	if err != nil || !reflect.DeepEqual(got, map[int]string{1: "a", 2: "b"}) {
+		t.Errorf("Unexpected result %v, err: %v", got, err)
+	}
+	if !reflect.DeepEqual(requested, []int{2, 3}) {
+		t.Errorf("Expected only missing keys to be loaded, got %v", requested)
+	}
+	if v, ok := cache.Get(2); !ok || v != "b" {
+		t.Errorf("Expected loaded value to be cached, got %v, ok: %v", v, ok)
+	}
+
+	loadErr := errors.New("backend down")
+	got, err = cache.MGetOrLoad(context.Background(), []int{1, 4}, func(ctx context.Context, missing []int) (map[int]string, error) {
+		return nil, loadErr
+	})
+	if !errors.Is(err, loadErr) || !reflect.DeepEqual(got, map[int]string{1: "a"}) {
+		t.Errorf("Expected partial result with error, got %v, err: %v", got, err)
+	}
+}
@@ -0,0 +1,190 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断器打开期间加载被拒绝时返回的错误
+var ErrCircuitOpen = errors.New("cachex: circuit open")
+
+// CircuitBreaker 加载函数的熔断策略，连续失败 Threshold 次后在 Cooldown 内拒绝所有加载
+// 冷却结束后进入半开状态，只放行一次试探加载，成功则恢复，失败则重新打开
+type CircuitBreaker struct {
+	// Threshold 触发熔断的连续失败次数，小于等于0时不启用
+	Threshold int
+	// Cooldown 熔断打开的时长，小于等于0时使用1秒
+	Cooldown time.Duration
+	// KeepStale 条目过期后旧值继续保留的时长，期间加载被熔断拒绝时返回旧值而不是 ErrCircuitOpen
+	// 旧值在重新加载成功、写入新值或删除时丢弃，小于等于0时使用5分钟
+	KeepStale time.Duration
+}
+
+// BreakerState 熔断器的状态
+type BreakerState int
+
+const (
+	// BreakerClosed 正常放行加载
+	BreakerClosed BreakerState = iota
+	// BreakerOpen 拒绝所有加载
+	BreakerOpen
+	// BreakerHalfOpen 冷却结束，正在进行或等待一次试探加载
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// breaker 熔断器的运行状态，整个缓存共享一个
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	state    BreakerState
+	probing  bool
+}
+
+// allow 判断是否放行一次加载，半开状态下只放行一个试探
+func (b *breaker) allow(opts CircuitBreaker, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= opts.cooldown() {
+		b.state = BreakerHalfOpen
+	}
+	switch b.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// loadOutcome 一次加载对熔断器的意义
+type loadOutcome int
+
+const (
+	loadSucceeded loadOutcome = iota
+	loadFailed
+	// loadInconclusive 数据不存在或调用方取消，无法说明数据源是否可用
+	loadInconclusive
+)
+
+// record 记录一次被放行的加载的结果，无法说明数据源状态的结果只结束试探，保持原有状态
+func (b *breaker) record(opts CircuitBreaker, outcome loadOutcome, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch outcome {
+	case loadInconclusive:
+		return
+	case loadSucceeded:
+		b.failures = 0
+		b.state = BreakerClosed
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= opts.Threshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+func (b *breaker) current(opts CircuitBreaker, now time.Time) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= opts.cooldown() {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+func (o CircuitBreaker) cooldown() time.Duration {
+	if o.Cooldown <= 0 {
+		return time.Second
+	}
+	return o.Cooldown
+}
+
+func (o CircuitBreaker) keepStale() time.Duration {
+	if o.KeepStale <= 0 {
+		return 5 * time.Minute
+	}
+	return o.KeepStale
+}
+
+// outcomeOf 判断加载错误是否说明数据源不可用，数据不存在和调用方取消不能说明
+func outcomeOf(err error) loadOutcome {
+	switch {
+	case err == nil:
+		return loadSucceeded
+	case errors.Is(err, ErrNotFound), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return loadInconclusive
+	}
+	return loadFailed
+}
+
+// staleValue 已过期条目保留下来的旧值，熔断器拒绝加载时返回
+type staleValue[V any] struct {
+	value V
+	until time.Time
+}
+
+// staleOnOpen 加载被熔断拒绝时返回键保留的旧值
+func (c *BaseCache[K, V]) staleOnOpen(s *cacheShard[K, V], key K, err error) (V, bool) {
+	var zero V
+	if !errors.Is(err, ErrCircuitOpen) {
+		return zero, false
+	}
+	s.mu.RLock()
+	sv, ok := s.stale[key]
+	s.mu.RUnlock()
+	if !ok || !c.now().Before(sv.until) {
+		return zero, false
+	}
+	return c.clone(sv.value), true
+}
+
+// 以下方法均需在持有写锁的情况下调用
+
+// keepStale 启用熔断时保留过期条目的旧值
+func (s *cacheShard[K, V]) keepStale(key K, item *cacheItemWrapper[K, V]) {
+	if s.c.opts.CircuitBreaker.Threshold <= 0 || item.negative {
+		return
+	}
+	if s.stale == nil {
+		s.stale = make(map[K]staleValue[V])
+	}
+	s.stale[key] = staleValue[V]{value: item.value, until: item.deadline.Add(s.c.opts.CircuitBreaker.keepStale())}
+}
+
+// sweepStale 删除超过保留时长的旧值
+func (s *cacheShard[K, V]) sweepStale(now time.Time) {
+	for key, sv := range s.stale {
+		if !now.Before(sv.until) {
+			delete(s.stale, key)
+		}
+	}
+}
+
+// BreakerState 返回加载熔断器当前的状态，没有启用熔断时始终为 BreakerClosed
+func (c *BaseCache[K, V]) BreakerState() BreakerState {
+	if c.opts.CircuitBreaker.Threshold <= 0 {
+		return BreakerClosed
+	}
+	return c.breaker.current(c.opts.CircuitBreaker, c.now())
+}
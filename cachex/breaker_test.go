@@ -0,0 +1,159 @@
+package cachex_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/llyb120/gotool/cachex/clocktest"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:          clock,
+		CircuitBreaker: cachex.CircuitBreaker{Threshold: 3, Cooldown: time.Minute},
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("backend down")
+	calls := 0
+	failing := func() (int, error) {
+		calls++
+		return 0, loadErr
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetOrSetFuncErr("a", failing); !errors.Is(err, loadErr) {
+			t.Fatalf("Expected loader error, got %v", err)
+		}
+	}
+	if s := cache.BreakerState(); s != cachex.BreakerOpen {
+		t.Fatalf("Expected breaker to open after 3 failures, got %v", s)
+	}
+	// 打开期间任何键都不会调用加载函数
+	if _, err := cache.GetOrSetFuncErr("b", failing); !errors.Is(err, cachex.ErrCircuitOpen) || calls != 3 {
+		t.Errorf("Expected ErrCircuitOpen without calling loader, calls: %d, err: %v", calls, err)
+	}
+
+	// 冷却结束后半开，试探失败重新打开
+	clock.Advance(time.Minute)
+	if s := cache.BreakerState(); s != cachex.BreakerHalfOpen {
+		t.Errorf("Expected half-open after cooldown, got %v", s)
+	}
+	cache.GetOrSetFuncErr("a", failing)
+	if s := cache.BreakerState(); s != cachex.BreakerOpen || calls != 4 {
+		t.Errorf("Expected failed probe to reopen, got %v, calls: %d", s, calls)
+	}
+
+	// 试探成功后恢复
+	clock.Advance(time.Minute)
+	if v, err := cache.GetOrSetFuncErr("a", func() (int, error) { return 1, nil }); err != nil || v != 1 {
+		t.Errorf("Expected probe to succeed, got %v, err: %v", v, err)
+	}
+	if s := cache.BreakerState(); s != cachex.BreakerClosed {
+		t.Errorf("Expected breaker to close after successful probe, got %v", s)
+	}
+}
+
+func TestCircuitBreakerServesStale(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var failing bool
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:                clock,
+		DefaultKeyExpire:     time.Second,
+		StaleWhileRevalidate: time.Hour,
+		CircuitBreaker:       cachex.CircuitBreaker{Threshold: 1, Cooldown: time.Minute},
+		Loader: func(ctx context.Context, key string) (int, error) {
+			if failing {
+				return 0, errors.New("backend down")
+			}
+			return 1, nil
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	failing = true
+	cache.GetOrSetFuncErr("b", func() (int, error) { return 0, errors.New("backend down") })
+	if cache.BreakerState() != cachex.BreakerOpen {
+		t.Fatalf("Expected breaker to open")
+	}
+	clock.Advance(2 * time.Second)
+	// 熔断期间已过期的条目继续返回旧值
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected stale value while circuit is open, got %v, ok: %v", v, ok)
+	}
+}
+
+func TestCircuitBreakerKeepStale(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:            clock,
+		DefaultKeyExpire: time.Second,
+		CheckInterval:    time.Second,
+		CircuitBreaker:   cachex.CircuitBreaker{Threshold: 1, Cooldown: time.Hour, KeepStale: time.Minute},
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("backend down")
+	failing := func() (int, error) { return 0, loadErr }
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	clock.Advance(2 * time.Second)
+	// 没有开启 StaleWhileRevalidate，过期的条目不再命中
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("Expected expired entry to miss")
+	}
+	// 打开熔断的那次加载失败时返回加载错误
+	if _, err := cache.GetOrSetFuncErr("a", failing); !errors.Is(err, loadErr) {
+		t.Fatalf("Expected loader error, got %v", err)
+	}
+	// 熔断拒绝加载时返回过期前的旧值
+	if v, err := cache.GetOrSetFuncErr("a", failing); err != nil || v != 1 {
+		t.Errorf("Expected stale value while circuit is open, got %v, err: %v", v, err)
+	}
+	// 删除后不再返回旧值
+	cache.Del("b")
+	if _, err := cache.GetOrSetFuncErr("b", failing); !errors.Is(err, cachex.ErrCircuitOpen) {
+		t.Errorf("Expected deleted key to have no stale value, got %v", err)
+	}
+	// 超过 KeepStale 后旧值被丢弃
+	clock.Advance(time.Minute)
+	if _, err := cache.GetOrSetFuncErr("c", failing); !errors.Is(err, cachex.ErrCircuitOpen) {
+		t.Errorf("Expected stale value to be dropped after KeepStale, got %v", err)
+	}
+}
+
+func TestCircuitBreakerCanceledProbe(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:          clock,
+		CircuitBreaker: cachex.CircuitBreaker{Threshold: 1, Cooldown: time.Minute},
+	})
+	defer cache.Close()
+
+	loadErr := errors.New("backend down")
+	cache.GetOrSetFuncErr("a", func() (int, error) { return 0, loadErr })
+	clock.Advance(time.Minute)
+
+	// 试探被调用方取消，不能说明数据源已恢复，保持半开并允许下一次试探
+	ctx, cancel := context.WithCancel(context.Background())
+	cache.GetOrSetFuncCtx(ctx, "a", func(ctx context.Context) (int, error) {
+		cancel()
+		return 0, ctx.Err()
+	})
+	if s := cache.BreakerState(); s != cachex.BreakerHalfOpen {
+		t.Fatalf("Expected canceled probe to keep the breaker half-open, got %v", s)
+	}
+	calls := 0
+	cache.GetOrSetFuncErr("a", func() (int, error) {
+		calls++
+		return 0, loadErr
+	})
+	if s := cache.BreakerState(); s != cachex.BreakerOpen || calls != 1 {
+		t.Errorf("Expected next probe to run and reopen the breaker, got %v, calls: %d", s, calls)
+	}
+}
@@ -0,0 +1,261 @@
+package cachex
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"sync"
+	"time"
+)
+
+// ByteCache 存放 []byte 值的大容量缓存，值按写入顺序追加到每个分片预先分配的环形缓冲区中
+// 索引只保存键的哈希到偏移量的映射，不含指针，GC 不需要扫描缓存内容，适合数 GB 的数据
+// 缓冲区写满时从最早写入的条目开始覆盖，过期和删除的条目在被覆盖时回收空间
+type ByteCache struct {
+	shards []*byteShard
+	seed   maphash.Seed
+	opts   ByteCacheOption
+}
+
+type ByteCacheOption struct {
+	// MaxBytes 所有分片的缓冲区总大小，包括每个条目的头部和键，小于等于0时为 64MB
+	MaxBytes int
+	// Shards 分片数量，小于等于0时为 16
+	Shards int
+	// DefaultExpire 使用 Set 写入的条目的有效期，小于等于0时永不过期
+	DefaultExpire time.Duration
+	// Clock 时间来源，为 nil 时使用系统时间
+	Clock Clock
+}
+
+// byteHeaderSize 条目头部的长度：过期时间(8) 键的哈希(8) 键长(2) 值长(4)
+const byteHeaderSize = 22
+
+// byteShard 一个分片的环形缓冲区
+// 未回绕时数据位于 [head, tail)，回绕后位于 [head, wrap) 和 [0, tail)
+type byteShard struct {
+	mu    sync.RWMutex
+	index map[uint64]uint32 // 键的哈希到条目偏移量
+	buf   []byte
+	head  int
+	tail  int
+	wrap  int // 回绕前数据的结束位置，未回绕时为 -1
+}
+
+// NewByteCache 创建一个 ByteCache，缓冲区在创建时一次性分配
+func NewByteCache(opts ByteCacheOption) *ByteCache {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = 64 << 20
+	}
+	if opts.Shards <= 0 {
+		opts.Shards = 16
+	}
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+	}
+	c := &ByteCache{
+		shards: make([]*byteShard, opts.Shards),
+		seed:   maphash.MakeSeed(),
+		opts:   opts,
+	}
+	// 偏移量使用 uint32 保存，单个分片的缓冲区不超过 4GB
+	size := int64(opts.MaxBytes / opts.Shards)
+	if size > math.MaxUint32 {
+		size = math.MaxUint32
+	}
+	for i := range c.shards {
+		c.shards[i] = &byteShard{
+			index: make(map[uint64]uint32),
+			buf:   make([]byte, size),
+			wrap:  -1,
+		}
+	}
+	return c
+}
+
+func (c *ByteCache) shard(hash uint64) *byteShard {
+	return c.shards[hash%uint64(len(c.shards))]
+}
+
+// Get 获取键对应的值，返回的是值的副本
+func (c *ByteCache) Get(key string) ([]byte, bool) {
+	value, _, ok := c.get(key)
+	return value, ok
+}
+
+// GetWithTTL 获取键对应的值及其剩余的有效期，永不过期的条目返回的剩余时间小于0
+func (c *ByteCache) GetWithTTL(key string) ([]byte, time.Duration, bool) {
+	return c.get(key)
+}
+
+// TTL 返回键剩余的有效期，永不过期时小于0
+func (c *ByteCache) TTL(key string) (time.Duration, bool) {
+	_, ttl, ok := c.get(key)
+	return ttl, ok
+}
+
+func (c *ByteCache) get(key string) ([]byte, time.Duration, bool) {
+	hash := maphash.String(c.seed, key)
+	s := c.shard(hash)
+	now := c.opts.Clock.Now()
+	s.mu.RLock()
+	off, ok := s.lookup(hash, key)
+	if !ok {
+		s.mu.RUnlock()
+		return nil, 0, false
+	}
+	deadline := s.deadline(off)
+	if deadline != 0 && deadline <= now.UnixNano() {
+		s.mu.RUnlock()
+		// 过期的条目从索引中删除，空间在被覆盖时回收
+		s.mu.Lock()
+		if cur, ok := s.index[hash]; ok && cur == off {
+			delete(s.index, hash)
+		}
+		s.mu.Unlock()
+		return nil, 0, false
+	}
+	value := append([]byte(nil), s.value(off)...)
+	s.mu.RUnlock()
+	ttl := time.Duration(-1)
+	if deadline != 0 {
+		ttl = time.Duration(deadline - now.UnixNano())
+	}
+	return value, ttl, true
+}
+
+// Set 写入键值对，使用 DefaultExpire 作为有效期
+func (c *ByteCache) Set(key string, value []byte) {
+	c.SetExpire(key, value, c.opts.DefaultExpire)
+}
+
+// SetExpire 写入键值对，并指定有效期，小于等于0时永不过期，值会被复制到缓冲区中
+// 条目超过单个分片的缓冲区大小或键超过 65535 字节时不会写入，并删除该键原有的值
+func (c *ByteCache) SetExpire(key string, value []byte, expire time.Duration) {
+	hash := maphash.String(c.seed, key)
+	s := c.shard(hash)
+	var deadline int64
+	if expire > 0 {
+		deadline = c.opts.Clock.Now().Add(expire).UnixNano()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size := byteHeaderSize + len(key) + len(value)
+	if len(key) > 0xffff || size > len(s.buf) {
+		delete(s.index, hash)
+		return
+	}
+	off := s.alloc(size)
+	entry := s.buf[off : off+size]
+	binary.LittleEndian.PutUint64(entry[0:], uint64(deadline))
+	binary.LittleEndian.PutUint64(entry[8:], hash)
+	binary.LittleEndian.PutUint16(entry[16:], uint16(len(key)))
+	binary.LittleEndian.PutUint32(entry[18:], uint32(len(value)))
+	copy(entry[byteHeaderSize:], key)
+	copy(entry[byteHeaderSize+len(key):], value)
+	s.index[hash] = uint32(off)
+}
+
+// Del 删除键
+func (c *ByteCache) Del(key string) {
+	hash := maphash.String(c.seed, key)
+	s := c.shard(hash)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lookup(hash, key); ok {
+		delete(s.index, hash)
+	}
+}
+
+// Len 返回未过期的条目数量
+func (c *ByteCache) Len() int {
+	now := c.opts.Clock.Now().UnixNano()
+	n := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for _, off := range s.index {
+			if deadline := s.deadline(off); deadline == 0 || deadline > now {
+				n++
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Clear 删除所有条目，缓冲区会被保留复用
+func (c *ByteCache) Clear() {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.index = make(map[uint64]uint32)
+		s.head, s.tail, s.wrap = 0, 0, -1
+		s.mu.Unlock()
+	}
+}
+
+// 以下方法均需在持有锁的情况下调用
+
+// lookup 查找键对应条目的偏移量，哈希相同但键不同时视为不存在
+func (s *byteShard) lookup(hash uint64, key string) (uint32, bool) {
+	off, ok := s.index[hash]
+	if !ok {
+		return 0, false
+	}
+	n := int(binary.LittleEndian.Uint16(s.buf[off+16:]))
+	start := int(off) + byteHeaderSize
+	if string(s.buf[start:start+n]) != key {
+		return 0, false
+	}
+	return off, true
+}
+
+func (s *byteShard) deadline(off uint32) int64 {
+	return int64(binary.LittleEndian.Uint64(s.buf[off:]))
+}
+
+func (s *byteShard) value(off uint32) []byte {
+	start := int(off) + byteHeaderSize + int(binary.LittleEndian.Uint16(s.buf[off+16:]))
+	return s.buf[start : start+int(binary.LittleEndian.Uint32(s.buf[off+18:]))]
+}
+
+func (s *byteShard) entrySize(off int) int {
+	return byteHeaderSize + int(binary.LittleEndian.Uint16(s.buf[off+16:])) + int(binary.LittleEndian.Uint32(s.buf[off+18:]))
+}
+
+// alloc 在缓冲区中分配 size 字节并返回偏移量，空间不足时覆盖最早写入的条目
+func (s *byteShard) alloc(size int) int {
+	for {
+		if s.wrap < 0 {
+			if s.tail+size <= len(s.buf) {
+				off := s.tail
+				s.tail += size
+				return off
+			}
+			if s.head == s.tail {
+				s.head, s.tail = 0, 0
+				continue
+			}
+			// 末尾放不下，回绕到缓冲区开头
+			s.wrap, s.tail = s.tail, 0
+			continue
+		}
+		if s.tail+size <= s.head {
+			off := s.tail
+			s.tail += size
+			return off
+		}
+		s.evictHead()
+	}
+}
+
+// evictHead 覆盖最早写入的条目，仍被索引引用时从索引中删除
+func (s *byteShard) evictHead() {
+	hash := binary.LittleEndian.Uint64(s.buf[s.head+8:])
+	if off, ok := s.index[hash]; ok && int(off) == s.head {
+		delete(s.index, hash)
+	}
+	s.head += s.entrySize(s.head)
+	if s.head == s.wrap {
+		s.head, s.wrap = 0, -1
+	}
+}
@@ -0,0 +1,30 @@
+package cachex
+
+import (
+	"strconv"
+	"testing"
+)
+
+func BenchmarkByteCacheParallel(b *testing.B) {
+	cache := NewByteCache(ByteCacheOption{MaxBytes: 64 << 20})
+	value := make([]byte, 128)
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		cache.Set(keys[i], value)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if i%10 == 0 {
+				cache.Set(key, value)
+			} else {
+				cache.Get(key)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package cachex
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestByteCache(t *testing.T) {
+	cache := NewByteCache(ByteCacheOption{MaxBytes: 1 << 20, Shards: 4})
+	var _ Cache[[]byte] = cache
+
+	value := []byte("hello")
+	cache.Set("a", value)
+	value[0] = 'j'
+	got, ok := cache.Get("a")
+	if !ok || string(got) != "hello" {
+		t.Fatalf("Expected hello, got %q, ok: %v", got, ok)
+	}
+	got[0] = 'x'
+	if got, _ := cache.Get("a"); string(got) != "hello" {
+		t.Errorf("Expected Get to return a copy, got %q", got)
+	}
+
+	cache.Set("a", []byte("world"))
+	if got, _ := cache.Get("a"); string(got) != "world" {
+		t.Errorf("Expected overwritten value, got %q", got)
+	}
+	if ttl, ok := cache.TTL("a"); !ok || ttl >= 0 {
+		t.Errorf("Expected negative ttl for key without expire, got %v", ttl)
+	}
+	cache.Del("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected a to be deleted")
+	}
+	cache.Set("empty", nil)
+	if got, ok := cache.Get("empty"); !ok || len(got) != 0 {
+		t.Errorf("Expected empty value, got %q, ok: %v", got, ok)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Errorf("Expected 1 entry, got %d", n)
+	}
+	cache.Clear()
+	if n := cache.Len(); n != 0 {
+		t.Errorf("Expected 0 entries after Clear, got %d", n)
+	}
+}
+
+func TestByteCacheExpire(t *testing.T) {
+	cache := NewByteCache(ByteCacheOption{DefaultExpire: 5 * time.Millisecond})
+	cache.Set("a", []byte("1"))
+	cache.SetExpire("b", []byte("2"), time.Minute)
+	if ttl, ok := cache.TTL("b"); !ok || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected ttl within a minute, got %v", ttl)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected a to expire")
+	}
+	if n := cache.Len(); n != 1 {
+		t.Errorf("Expected 1 entry, got %d", n)
+	}
+}
+
+func TestByteCacheOverwriteOldest(t *testing.T) {
+	// 单个分片 1KB，写入远超容量的数据后只保留最近写入的条目
+	cache := NewByteCache(ByteCacheOption{MaxBytes: 1024, Shards: 1})
+	value := bytes.Repeat([]byte("v"), 50)
+	for i := 0; i < 1000; i++ {
+		cache.Set(fmt.Sprint("key", i), value)
+	}
+	if got, ok := cache.Get("key999"); !ok || !bytes.Equal(got, value) {
+		t.Errorf("Expected latest key to be present")
+	}
+	if _, ok := cache.Get("key0"); ok {
+		t.Errorf("Expected oldest key to be overwritten")
+	}
+	if n := cache.Len(); n == 0 || n*(byteHeaderSize+6+len(value)) > 1024 {
+		t.Errorf("Expected entries to fit in the buffer, got %d", n)
+	}
+	for i := 1000 - cache.Len(); i < 1000; i++ {
+		if got, ok := cache.Get(fmt.Sprint("key", i)); !ok || !bytes.Equal(got, value) {
+			t.Fatalf("Expected key%d to be intact, got %q, ok: %v", i, got, ok)
+		}
+	}
+
+	cache.Set("huge", make([]byte, 2048))
+	if _, ok := cache.Get("huge"); ok {
+		t.Errorf("Expected entry larger than the buffer to be rejected")
+	}
+}
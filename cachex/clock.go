@@ -0,0 +1,36 @@
+package cachex
+
+import "time"
+
+// Clock 缓存使用的时间来源，测试中可以替换为 clocktest 中可手动推进的实现
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker 周期性触发的计时器
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	// Reset 修改触发周期，下一次触发在 d 之后
+	Reset(d time.Duration)
+}
+
+// systemClock 使用系统时间
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
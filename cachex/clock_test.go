@@ -0,0 +1,57 @@
+package cachex_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/llyb120/gotool/cachex/clocktest"
+)
+
+func TestBaseCacheClock(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{Clock: clock})
+	defer cache.Close()
+
+	cache.SetExpire("a", 1, time.Hour)
+	cache.SetExpire("b", 2, 2*time.Hour)
+	clock.Advance(59 * time.Minute)
+	if _, ttl, ok := cache.GetWithTTL("a"); !ok || ttl != time.Minute {
+		t.Errorf("Expected 1m left, got %v, ok: %v", ttl, ok)
+	}
+	clock.Advance(time.Minute)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected 'a' to expire exactly after an hour")
+	}
+	if _, ok := cache.Get("b"); !ok {
+		t.Errorf("Expected 'b' to be alive")
+	}
+}
+
+func TestBaseCacheClockSweep(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	swept := make(chan string, 1)
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:         clock,
+		CheckInterval: time.Minute,
+		OnEvict: func(key string, value int, reason cachex.EvictReason) {
+			swept <- key
+		},
+	})
+	defer cache.Close()
+
+	cache.SetExpire("a", 1, 30*time.Second)
+	// 后台清理协程启动需要时间，持续推进时钟直到条目被清理
+	for i := 0; i < 100; i++ {
+		clock.Advance(time.Minute)
+		select {
+		case key := <-swept:
+			if key != "a" {
+				t.Errorf("Expected 'a' to be swept, got %v", key)
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Errorf("Expected sweeper to follow the fake clock")
+}
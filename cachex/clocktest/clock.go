@@ -0,0 +1,99 @@
+package clocktest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+)
+
+// Clock 只能手动推进的时钟，用于在测试中精确控制过期而不需要等待
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*ticker
+}
+
+var _ cachex.Clock = (*Clock)(nil)
+
+// New 创建一个停在 now 的时钟
+func New(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker 创建随时钟推进而触发的计时器，与 time.Ticker 一样来不及接收的触发会被丢弃
+func (c *Clock) NewTicker(d time.Duration) cachex.Ticker {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &ticker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance 将时钟向前推进 d，并触发期间到期的计时器
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.set(c.now.Add(d))
+	c.mu.Unlock()
+}
+
+// Set 将时钟设置为 t，早于当前时间时不会触发计时器
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.set(t)
+	c.mu.Unlock()
+}
+
+func (c *Clock) set(t time.Time) {
+	c.now = t
+	for _, tk := range c.tickers {
+		for !tk.next.After(t) {
+			select {
+			case tk.c <- tk.next:
+			default:
+			}
+			tk.next = tk.next.Add(tk.period)
+		}
+	}
+}
+
+type ticker struct {
+	clock  *Clock
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+func (t *ticker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *ticker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for Ticker.Reset")
+	}
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.period = d
+	t.next = t.clock.now.Add(d)
+}
+
+func (t *ticker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, tk := range t.clock.tickers {
+		if tk == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := New(start)
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatalf("Expected ticker not to fire before its period")
+	default:
+	}
+	if got := clock.Now(); !got.Equal(start.Add(500 * time.Millisecond)) {
+		t.Errorf("Expected time to advance, got %v", got)
+	}
+
+	// 一次推进多个周期时只保留一次触发
+	clock.Advance(3 * time.Second)
+	select {
+	case tick := <-ticker.C():
+		if !tick.Equal(start.Add(time.Second)) {
+			t.Errorf("Expected first tick at 1s, got %v", tick)
+		}
+	default:
+		t.Fatalf("Expected ticker to fire")
+	}
+	select {
+	case <-ticker.C():
+		t.Fatalf("Expected missed ticks to be dropped")
+	default:
+	}
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	select {
+	case <-ticker.C():
+		t.Fatalf("Expected stopped ticker not to fire")
+	default:
+	}
+}
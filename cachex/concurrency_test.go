@@ -0,0 +1,91 @@
+package cachex
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 以下测试需要配合 -race 运行，覆盖读、写、加载、清理同时进行的情况
+
+func stressBaseCache(t *testing.T, opts OnceCacheOption[string, int]) {
+	cache := NewBaseCache(opts)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := strconv.Itoa(i % 32)
+				switch (g + i) % 8 {
+				case 0:
+					cache.SetExpire(key, i, time.Millisecond)
+				case 1:
+					cache.Del(key)
+				case 2:
+					cache.Touch(key, time.Millisecond)
+				case 3:
+					cache.GetOrSetFuncCtx(context.Background(), key, func(ctx context.Context) (int, error) {
+						return i, nil
+					})
+				case 4:
+					cache.MGet([]string{key, "0", "1"})
+				case 5:
+					Increment(cache, key, 1)
+				default:
+					if v, ok := cache.Get(key); ok && v < 0 {
+						t.Errorf("Unexpected value %d", v)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestBaseCacheConcurrent(t *testing.T) {
+	stressBaseCache(t, OnceCacheOption[string, int]{CheckInterval: time.Millisecond})
+}
+
+func TestBaseCacheConcurrentBounded(t *testing.T) {
+	stressBaseCache(t, OnceCacheOption[string, int]{
+		MaxEntries:    8,
+		CheckInterval: time.Millisecond,
+		OnEvict:       func(key string, value int, reason EvictReason) {},
+	})
+}
+
+func TestBaseCacheConcurrentSharded(t *testing.T) {
+	stressBaseCache(t, OnceCacheOption[string, int]{
+		Shards:               4,
+		MaxEntries:           16,
+		Loader:               func(ctx context.Context, key string) (int, error) { return 1, nil },
+		StaleWhileRevalidate: time.Millisecond,
+	})
+}
+
+// 热点键已在访问顺序的队首时读取只需要读锁
+func TestBoundedHotReadUsesReadLock(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 2})
+	defer cache.Close()
+	cache.Set("a", 1)
+
+	s := cache.shards[0]
+	s.mu.RLock()
+	done := make(chan struct{})
+	go func() {
+		cache.Get("a")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Expected hot read not to wait for the write lock")
+	}
+	s.mu.RUnlock()
+	<-done
+}
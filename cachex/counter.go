@@ -0,0 +1,62 @@
+package cachex
+
+import "time"
+
+// Number 可以原子增减的数值类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Increment 在缓存的锁内将键的值增加 delta 并返回新值，可用于并发计数
+// 键不存在或已失效时从0开始并使用 DefaultKeyExpire，已存在的键保留原有的过期时间
+func Increment[K comparable, V Number](c *BaseCache[K, V], key K, delta V) V {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return 0
+	}
+	now := c.now()
+	item := s.getItem(key, now)
+	if item == nil || item.negative {
+		expire := c.jitter(c.defaultExpire(key, delta))
+		if c.writer != nil && !c.writer.set(key, delta, expire) {
+			return 0
+		}
+		s.setItem(key, c.newItem(key, delta, expire))
+		s.invalidate(key)
+		return delta
+	}
+	value := item.value + delta
+	// 永不过期的条目写入 Store 时同样不设过期时间，其余按条目被删除的时间计算，
+	// 过了新鲜期仍在返回旧值的条目不会得到小于等于0（即永不过期）的有效期
+	var ttl time.Duration
+	if item.canExpire {
+		ttl = item.deadline.Sub(now)
+		if ttl <= 0 {
+			ttl = time.Nanosecond
+		}
+	}
+	if c.writer != nil && !c.writer.set(key, value, ttl) {
+		return item.value
+	}
+	// 直接修改值以保留过期时间和访问顺序，成本按新值重新计算
+	item.value = value
+	item.version = c.version.Add(1)
+	s.publish(item)
+	s.invalidate(key)
+	if s.maxCost > 0 {
+		cost := c.weigh(key, value)
+		s.cost += cost - item.cost
+		item.cost = cost
+		s.evictOverflow()
+	}
+	return value
+}
+
+// Decrement 在缓存的锁内将键的值减少 delta 并返回新值
+func Decrement[K comparable, V Number](c *BaseCache[K, V], key K, delta V) V {
+	return Increment(c, key, -delta)
+}
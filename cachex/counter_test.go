@@ -0,0 +1,101 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIncrement(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int64]{})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Increment(cache, "hits", 1)
+		}()
+	}
+	wg.Wait()
+	if v, _ := cache.Get("hits"); v != 100 {
+		t.Errorf("Expected 100, got %d", v)
+	}
+	if v := Decrement(cache, "hits", 30); v != 70 {
+		t.Errorf("Expected 70, got %d", v)
+	}
+}
+
+func TestIncrementKeepsExpire(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, float64]{})
+	defer cache.Close()
+	cache.SetExpire("a", 1.5, 20*time.Millisecond)
+
+	if v := Increment(cache, "a", 1); v != 2.5 {
+		t.Errorf("Expected 2.5, got %v", v)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected increment to keep the original expire")
+	}
+	if v := Increment(cache, "a", 1); v != 1 {
+		t.Errorf("Expected expired counter to restart from 0, got %v", v)
+	}
+}
+
+// recordingStore 记录每次写入的过期时间
+type recordingStore struct {
+	*MemoryStore
+	ttls []time.Duration
+}
+
+func (s *recordingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.ttls = append(s.ttls, ttl)
+	return s.MemoryStore.Set(ctx, key, value, ttl)
+}
+
+func TestIncrementStoreTTL(t *testing.T) {
+	store := &recordingStore{MemoryStore: NewMemoryStore()}
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		DefaultKeyExpire: time.Minute,
+		Store:            store,
+	})
+	defer cache.Close()
+
+	// 永不过期的键写入 Store 时不应带上 DefaultKeyExpire
+	cache.SetExpire("a", 1, 0)
+	Increment(cache, "a", 1)
+	if len(store.ttls) != 2 || store.ttls[1] != 0 {
+		t.Errorf("Expected non-expiring key to be written with ttl 0, got %v", store.ttls)
+	}
+
+	cache.SetExpire("b", 1, time.Hour)
+	Increment(cache, "b", 1)
+	if ttl := store.ttls[len(store.ttls)-1]; ttl <= time.Minute || ttl > time.Hour {
+		t.Errorf("Expected remaining ttl of the key, got %v", ttl)
+	}
+}
+
+func TestIncrementStoreTTLStale(t *testing.T) {
+	store := &recordingStore{MemoryStore: NewMemoryStore()}
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		DefaultKeyExpire:     10 * time.Millisecond,
+		StaleWhileRevalidate: time.Hour,
+		Loader: func(ctx context.Context, key string) (int, error) {
+			return 0, errors.New("no refresh")
+		},
+		Store: store,
+	})
+	defer cache.Close()
+
+	// 过了新鲜期的条目写入 Store 时使用到被删除为止的剩余时间
+	cache.Set("a", 1)
+	time.Sleep(20 * time.Millisecond)
+	Increment(cache, "a", 1)
+	if ttl := store.ttls[len(store.ttls)-1]; ttl <= time.Minute || ttl > time.Hour {
+		t.Errorf("Expected stale counter to keep a positive ttl, got %v", ttl)
+	}
+}
@@ -0,0 +1,70 @@
+package cachex
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entry 缓存条目的副本及其元数据
+type Entry[V any] struct {
+	Value V
+	// Created 条目的写入时间
+	Created time.Time
+	// Expire 条目的过期时间，永不过期时为零值
+	Expire time.Time
+	// TTL 条目剩余的有效期，永不过期时小于0
+	TTL time.Duration
+	// Version 条目的版本号，每次写入都会增大，可用于 GetIfChanged
+	Version uint64
+	// AccessCount 自写入以来被读取命中的次数
+	AccessCount uint64
+	// LastAccess 最近一次被读取命中的时间，从未读取时为零值
+	LastAccess time.Time
+}
+
+func newEntry[K comparable, V any](item *cacheItemWrapper[K, V], now time.Time) Entry[V] {
+	entry := Entry[V]{Value: item.value, Created: item.created, TTL: -1, Version: item.version}
+	entry.AccessCount, entry.LastAccess = item.access.load()
+	if item.canExpire {
+		entry.Expire = item.expire
+		if entry.TTL = item.expire.Sub(now); entry.TTL < 0 {
+			entry.TTL = 0
+		}
+	}
+	return entry
+}
+
+// Dump 返回所有未失效条目的副本，用于调试或管理接口展示缓存内容
+func (c *BaseCache[K, V]) Dump() map[K]Entry[V] {
+	now := c.now()
+	items := c.snapshotItems()
+	entries := make(map[K]Entry[V], len(items))
+	for i := range items {
+		entries[items[i].key] = newEntry(&items[i], now)
+	}
+	return entries
+}
+
+// jsonEntry 条目的 JSON 表示，ttl 使用可读的时长格式
+type jsonEntry[V any] struct {
+	Value   V          `json:"value"`
+	Created time.Time  `json:"created"`
+	Expire  *time.Time `json:"expire,omitempty"`
+	TTL     string     `json:"ttl,omitempty"`
+}
+
+// MarshalJSON 以键到条目的对象形式输出所有未失效的条目，键的类型需要能作为 JSON 对象的键
+func (c *BaseCache[K, V]) MarshalJSON() ([]byte, error) {
+	dump := c.Dump()
+	entries := make(map[K]jsonEntry[V], len(dump))
+	for key, e := range dump {
+		je := jsonEntry[V]{Value: e.Value, Created: e.Created}
+		if e.TTL >= 0 {
+			expire := e.Expire
+			je.Expire = &expire
+			je.TTL = e.TTL.String()
+		}
+		entries[key] = je
+	}
+	return json.Marshal(entries)
+}
@@ -0,0 +1,42 @@
+package cachex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDump(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, time.Minute)
+	cache.SetExpire("c", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	dump := cache.Dump()
+	if len(dump) != 2 {
+		t.Fatalf("Expected 2 live entries, got %v", dump)
+	}
+	if e := dump["a"]; e.Value != 1 || e.TTL >= 0 || !e.Expire.IsZero() {
+		t.Errorf("Expected permanent entry, got %+v", e)
+	}
+	if e := dump["b"]; e.Value != 2 || e.TTL <= 0 || e.TTL > time.Minute || e.Created.IsZero() {
+		t.Errorf("Expected entry with ttl, got %+v", e)
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	var out map[string]map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	if out["a"]["value"] != float64(1) || out["a"]["ttl"] != nil {
+		t.Errorf("Unexpected JSON for a: %v", out["a"])
+	}
+	if out["b"]["ttl"] == nil || out["b"]["expire"] == nil {
+		t.Errorf("Expected ttl and expire for b: %v", out["b"])
+	}
+}
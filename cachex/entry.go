@@ -0,0 +1,46 @@
+package cachex
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// itemAccess 条目的访问统计，由条目的所有副本共享
+type itemAccess struct {
+	count atomic.Uint64
+	last  atomic.Int64 // 最近一次访问的 UnixNano
+	ref   atomic.Bool  // 上次被 PolicyClock 检查之后是否被访问过
+}
+
+func (a *itemAccess) record(now time.Time) {
+	a.count.Add(1)
+	a.last.Store(now.UnixNano())
+	if !a.ref.Load() {
+		a.ref.Store(true)
+	}
+}
+
+func (a *itemAccess) load() (uint64, time.Time) {
+	count := a.count.Load()
+	if count == 0 {
+		return 0, time.Time{}
+	}
+	return count, time.Unix(0, a.last.Load())
+}
+
+// GetEntry 获取条目及其元数据，包括写入时间、过期时间、命中次数和最近访问时间
+// 用于排查键为什么是热点或为什么被淘汰，本身不计入命中次数，也不影响访问顺序和统计
+func (c *BaseCache[K, V]) GetEntry(key K) (Entry[V], bool) {
+	s := c.shard(key)
+	now := c.now()
+	s.mu.RLock()
+	p, ok := s.cache[key]
+	if !ok || p.negative || p.expired(now) {
+		s.mu.RUnlock()
+		return Entry[V]{}, false
+	}
+	item := *p
+	s.mu.RUnlock()
+	item.value = c.clone(item.value)
+	return newEntry(&item, now), true
+}
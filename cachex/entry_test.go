@@ -0,0 +1,40 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetEntry(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 10})
+	defer cache.Close()
+
+	if _, ok := cache.GetEntry("missing"); ok {
+		t.Errorf("Expected missing entry")
+	}
+	cache.SetExpire("key", 1, time.Minute)
+	entry, ok := cache.GetEntry("key")
+	if !ok || entry.Value != 1 || entry.AccessCount != 0 || !entry.LastAccess.IsZero() {
+		t.Fatalf("Expected unread entry, got %+v", entry)
+	}
+	if entry.Created.IsZero() || entry.Expire.Sub(entry.Created) != time.Minute {
+		t.Errorf("Expected created and expire times, got %+v", entry)
+	}
+
+	cache.Get("key")
+	cache.Get("key")
+	cache.MGet([]string{"key"})
+	entry, _ = cache.GetEntry("key")
+	if entry.AccessCount != 3 || entry.LastAccess.Before(entry.Created) {
+		t.Errorf("Expected 3 accesses, got %+v", entry)
+	}
+	if stats := cache.Stats(); stats.Hits != 3 {
+		t.Errorf("Expected GetEntry not to count as a hit, got %d hits", stats.Hits)
+	}
+
+	// 重新写入后统计重新开始
+	cache.Set("key", 2)
+	if entry, _ = cache.GetEntry("key"); entry.AccessCount != 0 {
+		t.Errorf("Expected access count to reset on overwrite, got %d", entry.AccessCount)
+	}
+}
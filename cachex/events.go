@@ -0,0 +1,104 @@
+package cachex
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType 缓存事件的类型
+type EventType int
+
+const (
+	// EventSet 写入或替换条目，包括加载函数写入的条目
+	EventSet EventType = iota
+	// EventHit 读取命中
+	EventHit
+	// EventMiss 读取未命中
+	EventMiss
+	// EventDeleted 条目被手动删除
+	EventDeleted
+	// EventExpired 条目过期被删除
+	EventExpired
+	// EventEvicted 条目因超出容量被淘汰
+	EventEvicted
+	// EventDestroyed 缓存被销毁，Key 为零值
+	EventDestroyed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventHit:
+		return "hit"
+	case EventMiss:
+		return "miss"
+	case EventDeleted:
+		return "deleted"
+	case EventExpired:
+		return "expired"
+	case EventEvicted:
+		return "evicted"
+	case EventDestroyed:
+		return "destroyed"
+	}
+	return "unknown"
+}
+
+// CacheEvent 缓存中发生的一次事件
+type CacheEvent[K comparable] struct {
+	Type EventType
+	Key  K
+	Time time.Time
+}
+
+// eventBus 事件通道，第一次调用 Events 后才开始投递
+type eventBus[K comparable] struct {
+	once    sync.Once
+	ch      atomic.Pointer[chan CacheEvent[K]]
+	dropped atomic.Uint64
+}
+
+// Events 返回缓存事件的通道，第一次调用后开始投递事件，多次调用返回同一个通道
+// 投递不会阻塞缓存的操作，通道已满时事件被丢弃并计入 DroppedEvents
+// 缓存销毁后发送 EventDestroyed，通道不会被关闭
+func (c *BaseCache[K, V]) Events() <-chan CacheEvent[K] {
+	c.events.once.Do(func() {
+		n := c.opts.EventBuffer
+		if n <= 0 {
+			n = 1024
+		}
+		ch := make(chan CacheEvent[K], n)
+		c.events.ch.Store(&ch)
+	})
+	return *c.events.ch.Load()
+}
+
+// DroppedEvents 返回因通道已满而被丢弃的事件数量
+func (c *BaseCache[K, V]) DroppedEvents() uint64 {
+	return c.events.dropped.Load()
+}
+
+func (c *BaseCache[K, V]) emit(t EventType, key K) {
+	ch := c.events.ch.Load()
+	if ch == nil {
+		return
+	}
+	select {
+	case *ch <- CacheEvent[K]{Type: t, Key: key, Time: c.now()}:
+	default:
+		c.events.dropped.Add(1)
+	}
+}
+
+// recordAccess 记录一次读取的统计和事件
+func (c *BaseCache[K, V]) recordAccess(key K, hit bool) {
+	if hit {
+		c.stats.hits.Add(1)
+		c.emit(EventHit, key)
+	} else {
+		c.stats.misses.Add(1)
+		c.emit(EventMiss, key)
+	}
+}
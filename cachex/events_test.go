@@ -0,0 +1,59 @@
+package cachex
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 1})
+	events := cache.Events()
+	if cache.Events() != events {
+		t.Errorf("Expected Events to return the same channel")
+	}
+
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.Get("b")
+	cache.Set("b", 2) // 淘汰 a
+	cache.SetExpire("c", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	cache.Get("c")
+	cache.Set("d", 4)
+	cache.Del("d")
+	cache.Close()
+
+	var got []EventType
+	for len(events) > 0 {
+		got = append(got, (<-events).Type)
+	}
+	want := []EventType{
+		EventSet, EventHit, EventMiss,
+		EventSet, EventEvicted,
+		EventSet, EventEvicted,
+		EventExpired, EventMiss,
+		EventSet, EventDeleted,
+		EventDestroyed,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestEventsDropped(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, int]{EventBuffer: 2})
+	defer cache.Close()
+
+	cache.Set(0, 0)
+	cache.Events()
+	for i := 0; i < 5; i++ {
+		cache.Set(i, i)
+	}
+	if n := len(cache.Events()); n != 2 {
+		t.Errorf("Expected buffered events to be capped, got %d", n)
+	}
+	if n := cache.DroppedEvents(); n != 3 {
+		t.Errorf("Expected 3 dropped events, got %d", n)
+	}
+}
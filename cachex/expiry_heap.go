@@ -0,0 +1,34 @@
+package cachex
+
+// expiryHeap 按过期时间排序的最小堆，实现 heap.Interface
+type expiryHeap[K comparable, V any] []*cacheItemWrapper[K, V]
+
+func (h expiryHeap[K, V]) Len() int {
+	return len(h)
+}
+
+func (h expiryHeap[K, V]) Less(i, j int) bool {
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h expiryHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *expiryHeap[K, V]) Push(x any) {
+	item := x.(*cacheItemWrapper[K, V])
+	item.heapIndex = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expiryHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.heapIndex = -1
+	*h = old[:n-1]
+	return item
+}
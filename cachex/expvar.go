@@ -0,0 +1,86 @@
+package cachex
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// expvarSource 由 PublishExpvar 发布的变量，stats 指向当前发布该名称的缓存，缓存销毁后置为 nil
+type expvarSource struct {
+	stats atomic.Pointer[func() CacheStats]
+}
+
+// expvarSources 记录 PublishExpvar 发布过的名称，expvar 不支持删除已发布的变量，重复发布时改为指向新的缓存
+var expvarSources = struct {
+	mu sync.Mutex
+	m  map[string]*expvarSource
+}{m: make(map[string]*expvarSource)}
+
+// expvarState 缓存发布的所有变量，销毁时统一解除指向
+type expvarState struct {
+	mu      sync.Mutex
+	stats   *func() CacheStats
+	sources []*expvarSource
+}
+
+// PublishExpvar 以 name 为名称把缓存的统计数据发布到 expvar，可通过 /debug/vars 查看
+// 发布的值包含 size、hits、misses、hit_ratio、loads、load_failures 和 evictions，每次读取时实时计算
+// name 已由 PublishExpvar 发布过时改为发布该缓存的数据，被其他变量占用时返回错误
+// 缓存销毁后该名称的值为 null，不再引用缓存
+func (c *BaseCache[K, V]) PublishExpvar(name string) error {
+	expvarSources.mu.Lock()
+	defer expvarSources.mu.Unlock()
+
+	src, ok := expvarSources.m[name]
+	if !ok {
+		if expvar.Get(name) != nil {
+			return fmt.Errorf("cachex: expvar %q is already published", name)
+		}
+		src = &expvarSource{}
+		expvar.Publish(name, expvar.Func(src.value))
+		expvarSources.m[name] = src
+	}
+
+	c.expvars.mu.Lock()
+	defer c.expvars.mu.Unlock()
+	if c.closed.Load() {
+		return nil
+	}
+	if c.expvars.stats == nil {
+		stats := c.Stats
+		c.expvars.stats = &stats
+	}
+	src.stats.Store(c.expvars.stats)
+	c.expvars.sources = append(c.expvars.sources, src)
+	return nil
+}
+
+// unpublishExpvar 解除该缓存发布的变量对缓存的引用，已被其他缓存重新发布的名称不受影响
+func (c *BaseCache[K, V]) unpublishExpvar() {
+	c.expvars.mu.Lock()
+	defer c.expvars.mu.Unlock()
+	for _, src := range c.expvars.sources {
+		src.stats.CompareAndSwap(c.expvars.stats, nil)
+	}
+	c.expvars.sources = nil
+	c.expvars.stats = nil
+}
+
+func (s *expvarSource) value() any {
+	fn := s.stats.Load()
+	if fn == nil {
+		return nil
+	}
+	stats := (*fn)()
+	return map[string]any{
+		"size":          stats.Size,
+		"hits":          stats.Hits,
+		"misses":        stats.Misses,
+		"hit_ratio":     stats.HitRatio(),
+		"loads":         stats.Loads,
+		"load_failures": stats.LoadFailures,
+		"evictions":     stats.Evictions,
+	}
+}
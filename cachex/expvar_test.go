@@ -0,0 +1,81 @@
+package cachex
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// expvarSeq 为每次运行的测试生成不同的名称，-count 大于1时不会重复发布
+var expvarSeq atomic.Int64
+
+func expvarName(t *testing.T) string {
+	return fmt.Sprintf("%s_%d", t.Name(), expvarSeq.Add(1))
+}
+
+func TestPublishExpvar(t *testing.T) {
+	name := expvarName(t)
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 1})
+	defer cache.Close()
+	if err := cache.PublishExpvar(name); err != nil {
+		t.Fatalf("PublishExpvar failed: %v", err)
+	}
+
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.Get("b")
+	cache.Set("b", 2)
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("Expected expvar to be published")
+	}
+	var got struct {
+		Size      int     `json:"size"`
+		Hits      uint64  `json:"hits"`
+		Misses    uint64  `json:"misses"`
+		HitRatio  float64 `json:"hit_ratio"`
+		Evictions uint64  `json:"evictions"`
+	}
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("Expected JSON value, got %q: %v", v.String(), err)
+	}
+	if got.Size != 1 || got.Hits != 1 || got.Misses != 1 || got.HitRatio != 0.5 || got.Evictions != 1 {
+		t.Errorf("Unexpected expvar value: %+v", got)
+	}
+}
+
+func TestPublishExpvarReuse(t *testing.T) {
+	name := expvarName(t)
+	old := NewBaseCache(OnceCacheOption[string, int]{})
+	if err := old.PublishExpvar(name); err != nil {
+		t.Fatalf("PublishExpvar failed: %v", err)
+	}
+	old.Close()
+	if v := expvar.Get(name).String(); v != "null" {
+		t.Errorf("Expected closed cache to be unpublished, got %s", v)
+	}
+
+	// 同一个名称可以再次发布，改为指向新的缓存
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	if err := cache.PublishExpvar(name); err != nil {
+		t.Fatalf("Expected name to be reused, got %v", err)
+	}
+	cache.Set("a", 1)
+	var got struct {
+		Size int `json:"size"`
+	}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &got); err != nil || got.Size != 1 {
+		t.Errorf("Expected expvar to follow the new cache, got %+v, err: %v", got, err)
+	}
+
+	// 被其他变量占用的名称返回错误
+	other := expvarName(t)
+	expvar.NewInt(other)
+	if err := cache.PublishExpvar(other); err == nil {
+		t.Errorf("Expected error for a name published by someone else")
+	}
+}
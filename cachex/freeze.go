@@ -0,0 +1,75 @@
+package cachex
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// FrozenCache 不可修改的只读缓存，用于启动时构建一次、之后被高频读取的配置类数据
+// 数据保存在普通 map 中，通过原子指针发布，读取不加锁也没有过期、淘汰和统计的开销
+// 需要更新时使用 Replace 整体替换，正在进行的读取和遍历继续使用替换前的数据
+type FrozenCache[K comparable, V any] struct {
+	data atomic.Pointer[frozenData[K, V]]
+}
+
+type frozenData[K comparable, V any] struct {
+	values map[K]V
+	at     time.Time
+}
+
+// Freeze 复制 src 创建只读缓存，之后对 src 的修改不会影响缓存
+func Freeze[K comparable, V any](src map[K]V) *FrozenCache[K, V] {
+	f := &FrozenCache[K, V]{}
+	f.Replace(src)
+	return f
+}
+
+// Replace 复制 src 并原子地替换缓存中的全部数据
+func (f *FrozenCache[K, V]) Replace(src map[K]V) {
+	values := make(map[K]V, len(src))
+	for key, value := range src {
+		values[key] = value
+	}
+	f.data.Store(&frozenData[K, V]{values: values, at: time.Now()})
+}
+
+func (f *FrozenCache[K, V]) Get(key K) (V, bool) {
+	value, ok := f.data.Load().values[key]
+	return value, ok
+}
+
+// Entry 返回的条目永不过期，Created 为数据被冻结的时刻
+func (f *FrozenCache[K, V]) Entry(key K) (Entry[V], bool) {
+	d := f.data.Load()
+	value, ok := d.values[key]
+	if !ok {
+		return Entry[V]{}, false
+	}
+	return Entry[V]{Value: value, Created: d.at, TTL: -1}, true
+}
+
+func (f *FrozenCache[K, V]) Keys() []K {
+	values := f.data.Load().values
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (f *FrozenCache[K, V]) Range(fn func(key K, value V) bool) {
+	for key, value := range f.data.Load().values {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+func (f *FrozenCache[K, V]) Len() int {
+	return len(f.data.Load().values)
+}
+
+// Time 数据被冻结的时刻，Replace 后为替换的时刻
+func (f *FrozenCache[K, V]) Time() time.Time {
+	return f.data.Load().at
+}
@@ -0,0 +1,47 @@
+package cachex
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	src := map[string]int{"a": 1, "b": 2}
+	var cache ReadOnlyCache[string, int] = Freeze(src)
+	src["c"] = 3
+
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+	if _, ok := cache.Get("c"); ok {
+		t.Errorf("Expected later changes to the source to be ignored")
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected 2 entries, got %d", n)
+	}
+	if e, ok := cache.Entry("b"); !ok || e.Value != 2 || e.TTL != -1 || !e.Created.Equal(cache.Time()) {
+		t.Errorf("Unexpected entry: %+v, ok: %v", e, ok)
+	}
+}
+
+func TestFrozenCacheReplace(t *testing.T) {
+	cache := Freeze(map[int]int{1: 1})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				cache.Get(1)
+				cache.Range(func(key, value int) bool { return true })
+			}
+		}()
+	}
+	for i := 2; i < 100; i++ {
+		cache.Replace(map[int]int{1: i})
+	}
+	wg.Wait()
+	if v, _ := cache.Get(1); v != 99 {
+		t.Errorf("Expected replaced value 99, got %d", v)
+	}
+}
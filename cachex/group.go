@@ -0,0 +1,106 @@
+package cachex
+
+import (
+	"context"
+	"time"
+)
+
+// GroupKey 分组中的键，命名空间与键分开保存，不会因为分隔符产生冲突
+type GroupKey struct {
+	Namespace string
+	Key       string
+}
+
+// Group 在同一个缓存之上划分多个命名空间，所有命名空间共享容量限制和同一个后台清理协程
+type Group[T any] struct {
+	cache *BaseCache[GroupKey, T]
+}
+
+// NewGroup 创建分组，opts 作用于底层共享的缓存
+func NewGroup[T any](opts OnceCacheOption[GroupKey, T]) *Group[T] {
+	return &Group[T]{cache: NewBaseCache(opts)}
+}
+
+// Namespace 返回指定命名空间的视图，相同名称的视图访问同一批数据
+func (g *Group[T]) Namespace(name string) *Namespace[T] {
+	return &Namespace[T]{group: g, name: name}
+}
+
+// Close 销毁分组及其所有命名空间
+func (g *Group[T]) Close() {
+	g.cache.Close()
+}
+
+// Closed 判断分组是否已经销毁
+func (g *Group[T]) Closed() bool {
+	return g.cache.Closed()
+}
+
+// Stats 返回所有命名空间合计的统计信息
+func (g *Group[T]) Stats() CacheStats {
+	return g.cache.Stats()
+}
+
+// Namespace 分组中的一个命名空间，只能访问自身的键
+type Namespace[T any] struct {
+	group *Group[T]
+	name  string
+}
+
+var _ Cache[int] = (*Namespace[int])(nil)
+
+func (n *Namespace[T]) key(key string) GroupKey {
+	return GroupKey{Namespace: n.name, Key: key}
+}
+
+// Name 返回命名空间的名称
+func (n *Namespace[T]) Name() string {
+	return n.name
+}
+
+func (n *Namespace[T]) Get(key string) (T, bool) {
+	return n.group.cache.Get(n.key(key))
+}
+
+func (n *Namespace[T]) Set(key string, value T) {
+	n.group.cache.Set(n.key(key), value)
+}
+
+func (n *Namespace[T]) SetExpire(key string, value T, expire time.Duration) {
+	n.group.cache.SetExpire(n.key(key), value, expire)
+}
+
+func (n *Namespace[T]) Del(key string) {
+	n.group.cache.Del(n.key(key))
+}
+
+func (n *Namespace[T]) GetOrSetFuncCtx(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	return n.group.cache.GetOrSetFuncCtx(ctx, n.key(key), fn)
+}
+
+// Keys 返回命名空间中所有未失效的键
+func (n *Namespace[T]) Keys() []string {
+	var keys []string
+	for _, k := range n.group.cache.Keys() {
+		if k.Namespace == n.name {
+			keys = append(keys, k.Key)
+		}
+	}
+	return keys
+}
+
+// Len 返回命名空间中未失效的条目数量
+func (n *Namespace[T]) Len() int {
+	return len(n.Keys())
+}
+
+// Clear 删除命名空间中的所有条目，其他命名空间不受影响
+func (n *Namespace[T]) Clear() {
+	var keys []GroupKey
+	for _, k := range n.group.cache.Keys() {
+		if k.Namespace == n.name {
+			keys = append(keys, k)
+		}
+	}
+	n.group.cache.MDel(keys...)
+}
@@ -0,0 +1,56 @@
+package cachex
+
+import (
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	group := NewGroup(OnceCacheOption[GroupKey, string]{CheckInterval: 5 * time.Millisecond})
+	defer group.Close()
+	users := group.Namespace("users")
+	orders := group.Namespace("orders")
+
+	users.Set("1", "alice")
+	orders.Set("1", "book")
+	users.SetExpire("2", "bob", 10*time.Millisecond)
+	if v, _ := users.Get("1"); v != "alice" {
+		t.Errorf("Expected alice, got %v", v)
+	}
+	if v, _ := orders.Get("1"); v != "book" {
+		t.Errorf("Expected book, got %v", v)
+	}
+	if v, _ := group.Namespace("users").Get("1"); v != "alice" {
+		t.Errorf("Expected namespaces with the same name to share data, got %v", v)
+	}
+
+	keys := users.Keys()
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "1" || keys[1] != "2" {
+		t.Errorf("Expected users keys [1 2], got %v", keys)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := group.Stats().Size; n != 2 {
+		t.Errorf("Expected shared sweeper to remove expired entry, size: %d", n)
+	}
+
+	users.Clear()
+	if users.Len() != 0 || orders.Len() != 1 {
+		t.Errorf("Expected Clear to affect only its namespace, users: %d, orders: %d", users.Len(), orders.Len())
+	}
+}
+
+func TestGroupSingleSweeper(t *testing.T) {
+	before := runtime.NumGoroutine()
+	group := NewGroup(OnceCacheOption[GroupKey, int]{CheckInterval: time.Millisecond})
+	defer group.Close()
+	for i := 0; i < 50; i++ {
+		group.Namespace(string(rune('a'+i))).Set("k", i)
+	}
+	// 底层只有生命周期和清理两个协程，与命名空间的数量无关
+	if n := runtime.NumGoroutine() - before; n > 2 {
+		t.Errorf("Expected at most 2 goroutines, got %d", n)
+	}
+}
@@ -0,0 +1,39 @@
+package cachex
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type benchCache interface {
+	Get(key uint64) (uint64, bool)
+	Set(key uint64, value uint64)
+}
+
+// benchmarkHitRatio 使用 Zipf 分布模拟热点集中的访问，未命中时写入缓存
+func benchmarkHitRatio(b *testing.B, cache benchCache) {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.01, 1, 100000)
+	hits := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := zipf.Uint64()
+		if _, ok := cache.Get(key); ok {
+			hits++
+		} else {
+			cache.Set(key, key)
+		}
+	}
+	b.ReportMetric(float64(hits)*100/float64(b.N), "hit%")
+}
+
+func BenchmarkHitRatioLRU(b *testing.B) {
+	benchmarkHitRatio(b, NewLRUCache(LRUCacheOption[uint64, uint64]{Capacity: 1000}))
+}
+
+func BenchmarkHitRatioTinyLFU(b *testing.B) {
+	benchmarkHitRatio(b, NewTinyLFU(TinyLFUOption[uint64, uint64]{Capacity: 1000}))
+}
+
+func BenchmarkHitRatioARC(b *testing.B) {
+	benchmarkHitRatio(b, NewARCCache(ARCCacheOption[uint64, uint64]{Capacity: 1000}))
+}
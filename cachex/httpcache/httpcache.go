@@ -0,0 +1,331 @@
+// Package httpcache 基于 cachex 缓存 GET 请求响应的 http 中间件
+package httpcache
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+)
+
+// Response 缓存的响应
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Stored 响应写入缓存的时间
+	Stored time.Time
+	// Expires 响应的过期时间
+	Expires time.Time
+}
+
+// Options 中间件的配置
+type Options struct {
+	// MaxEntries 最多缓存的 URL 数量，超出后淘汰最久未访问的 URL，小于等于0时为1000
+	MaxEntries int
+	// CheckInterval 清理过期响应的间隔，小于等于0时只在读取时删除
+	CheckInterval time.Duration
+	// DefaultTTL 响应没有通过 Cache-Control 或 Expires 指定有效期时使用的有效期，小于等于0时不缓存这类响应
+	DefaultTTL time.Duration
+	// MaxBodySize 可缓存的响应体的最大字节数，超过时不缓存，小于等于0时为 1MB
+	MaxBodySize int
+	// Key 计算缓存键的函数，为空时使用请求的 Host 加上路径和查询参数，例如 example.com/a?x=1
+	// 服务端收到的请求 URL 中不含 Host，自定义时也应区分 Host，否则不同的虚拟主机会共享缓存的响应
+	Key func(r *http.Request) string
+}
+
+// variants 同一个 URL 按 Vary 请求头区分的多个响应
+type variants struct {
+	mu        sync.Mutex
+	vary      []string             // 响应的 Vary 头中的请求头名称
+	responses map[string]*Response // 请求头取值到响应
+}
+
+// Middleware 缓存 GET 请求的响应，包括状态码、响应头和响应体
+// 有效期按响应的 Cache-Control 中的 s-maxage、max-age 或 Expires 计算，
+// 带有 no-store、no-cache、private 或 Set-Cookie 的响应不会被缓存
+type Middleware struct {
+	cache *cachex.BaseCache[string, *variants]
+	opts  Options
+}
+
+// New 创建中间件
+func New(opts Options) *Middleware {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = 1000
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 1 << 20
+	}
+	if opts.Key == nil {
+		opts.Key = func(r *http.Request) string { return r.Host + r.URL.RequestURI() }
+	}
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, *variants]{
+		MaxEntries:    opts.MaxEntries,
+		CheckInterval: opts.CheckInterval,
+	})
+	return &Middleware{cache: cache, opts: opts}
+}
+
+// Handler 包装 next，命中缓存的 GET 请求直接返回缓存的响应并带上 X-Cache: HIT 和 Age 头
+// 请求带有 Authorization 头时不使用缓存，请求的 Cache-Control 为 no-cache 或 no-store 时跳过缓存读取
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := m.opts.Key(r)
+		now := time.Now()
+		reqCC := parseCacheControl(r.Header)
+		if _, bypass := reqCC["no-cache"]; !bypass {
+			if _, bypass = reqCC["no-store"]; !bypass {
+				if resp := m.lookup(key, r, now); resp != nil {
+					writeResponse(w, resp, now)
+					return
+				}
+			}
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK, limit: m.opts.MaxBodySize}
+		next.ServeHTTP(rec, r)
+		if _, noStore := reqCC["no-store"]; noStore || rec.overflow {
+			return
+		}
+		rec.finish()
+		if ttl := m.ttl(rec.status, rec.header, now); ttl > 0 {
+			m.store(key, r, &Response{
+				Status:  rec.status,
+				Header:  rec.header,
+				Body:    rec.body,
+				Stored:  now,
+				Expires: now.Add(ttl),
+			}, ttl)
+		}
+	})
+}
+
+// Invalidate 删除 URL 对应的所有响应，key 与 Options.Key 的计算结果一致，默认为 Host 加上路径和查询参数
+func (m *Middleware) Invalidate(key string) {
+	m.cache.Del(key)
+}
+
+// InvalidateFunc 删除所有缓存键满足 match 的响应，例如按路径前缀失效
+func (m *Middleware) InvalidateFunc(match func(key string) bool) {
+	var keys []string
+	for _, key := range m.cache.Keys() {
+		if match(key) {
+			keys = append(keys, key)
+		}
+	}
+	m.cache.MDel(keys...)
+}
+
+// Clear 删除所有缓存的响应
+func (m *Middleware) Clear() {
+	m.cache.Clear()
+}
+
+// Close 销毁底层缓存
+func (m *Middleware) Close() {
+	m.cache.Close()
+}
+
+// Stats 返回底层缓存的统计数据
+func (m *Middleware) Stats() cachex.CacheStats {
+	return m.cache.Stats()
+}
+
+func (m *Middleware) lookup(key string, r *http.Request, now time.Time) *Response {
+	v, ok := m.cache.Get(key)
+	if !ok {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	resp := v.responses[varyKey(v.vary, r)]
+	if resp == nil || !now.Before(resp.Expires) {
+		return nil
+	}
+	return resp
+}
+
+// store 保存响应，Vary 头改变时丢弃该 URL 之前按旧的请求头保存的响应
+func (m *Middleware) store(key string, r *http.Request, resp *Response, ttl time.Duration) {
+	vary := varyHeaders(resp.Header)
+	for {
+		v, ok := m.cache.Get(key)
+		if !ok {
+			v = &variants{vary: vary, responses: map[string]*Response{varyKey(vary, r): resp}}
+			if m.cache.SetIfAbsent(key, v) {
+				m.cache.Touch(key, ttl)
+				return
+			}
+			continue
+		}
+		v.mu.Lock()
+		if !equalStrings(v.vary, vary) {
+			v.vary, v.responses = vary, make(map[string]*Response)
+		}
+		v.responses[varyKey(vary, r)] = resp
+		// URL 的有效期取所有响应中最晚的过期时间，过期的响应在读取时被忽略
+		latest := resp.Expires
+		for k, other := range v.responses {
+			if !resp.Stored.Before(other.Expires) {
+				delete(v.responses, k)
+			} else if other.Expires.After(latest) {
+				latest = other.Expires
+			}
+		}
+		v.mu.Unlock()
+		m.cache.Touch(key, latest.Sub(resp.Stored))
+		return
+	}
+}
+
+// ttl 根据响应计算有效期，返回0表示不缓存
+func (m *Middleware) ttl(status int, header http.Header, now time.Time) time.Duration {
+	switch status {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusMovedPermanently, http.StatusNotFound, http.StatusGone:
+	default:
+		return 0
+	}
+	if header.Get("Set-Cookie") != "" || header.Get("Vary") == "*" {
+		return 0
+	}
+	cc := parseCacheControl(header)
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if _, ok := cc[directive]; ok {
+			return 0
+		}
+	}
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if v, ok := cc[directive]; ok {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds <= 0 {
+				return 0
+			}
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	if v := header.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0
+		}
+		return expires.Sub(now)
+	}
+	return m.opts.DefaultTTL
+}
+
+// parseCacheControl 解析 Cache-Control 头，指令名称转为小写
+func parseCacheControl(header http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, line := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(part, "=")
+			cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return cc
+}
+
+// varyHeaders 返回响应 Vary 头中规范化并排序后的请求头名称
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, line := range header.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// varyKey 按 Vary 中的请求头取值区分同一个 URL 的不同响应
+func varyKey(vary []string, r *http.Request) string {
+	var b strings.Builder
+	for _, name := range vary {
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(r.Header.Values(name), ","))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, resp *Response, now time.Time) {
+	header := w.Header()
+	for name, values := range resp.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	header.Set("X-Cache", "HIT")
+	header.Set("Age", strconv.Itoa(int(now.Sub(resp.Stored)/time.Second)))
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// recorder 在写给客户端的同时记录响应，响应体超过 limit 时停止记录
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	header   http.Header
+	body     []byte
+	limit    int
+	wrote    bool
+	overflow bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wrote {
+		return
+	}
+	r.wrote = true
+	r.status = status
+	r.header = r.ResponseWriter.Header().Clone()
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	if !r.wrote {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.overflow {
+		if len(r.body)+len(p) > r.limit {
+			r.overflow, r.body = true, nil
+		} else {
+			r.body = append(r.body, p...)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// finish 处理没有写入任何内容的响应
+func (r *recorder) finish() {
+	if !r.wrote {
+		r.status = http.StatusOK
+		r.header = r.ResponseWriter.Header().Clone()
+	}
+}
@@ -0,0 +1,182 @@
+package httpcache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func serve(h http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	m := New(Options{})
+	defer m.Close()
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("X-Call", fmt.Sprint(n))
+		fmt.Fprintf(w, "body %d", n)
+	}))
+
+	first := serve(h, "/a?x=1", nil)
+	second := serve(h, "/a?x=1", nil)
+	if calls.Load() != 1 {
+		t.Fatalf("Expected one upstream call, got %d", calls.Load())
+	}
+	if second.Code != http.StatusOK || second.Body.String() != first.Body.String() || second.Header().Get("X-Call") != "1" {
+		t.Errorf("Expected cached response, got %d %q", second.Code, second.Body.String())
+	}
+	if second.Header().Get("X-Cache") != "HIT" || second.Header().Get("Age") == "" {
+		t.Errorf("Expected cache headers, got %v", second.Header())
+	}
+
+	serve(h, "/a?x=2", nil)
+	if calls.Load() != 2 {
+		t.Errorf("Expected a different URL to miss")
+	}
+
+	m.Invalidate("example.com/a?x=1")
+	serve(h, "/a?x=1", nil)
+	if calls.Load() != 3 {
+		t.Errorf("Expected invalidated URL to miss")
+	}
+
+	serve(h, "/a?x=1", http.Header{"Cache-Control": {"no-cache"}})
+	if calls.Load() != 4 {
+		t.Errorf("Expected no-cache request to bypass the cache")
+	}
+	serve(h, "/a?x=1", http.Header{"Authorization": {"token"}})
+	if calls.Load() != 5 {
+		t.Errorf("Expected authorized request to bypass the cache")
+	}
+}
+
+func TestMiddlewareVary(t *testing.T) {
+	var calls atomic.Int32
+	m := New(Options{})
+	defer m.Close()
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprint(w, r.Header.Get("Accept-Language"))
+	}))
+
+	en := http.Header{"Accept-Language": {"en"}}
+	zh := http.Header{"Accept-Language": {"zh"}}
+	serve(h, "/page", en)
+	serve(h, "/page", zh)
+	if got := serve(h, "/page", en).Body.String(); got != "en" {
+		t.Errorf("Expected en variant, got %q", got)
+	}
+	if got := serve(h, "/page", zh).Body.String(); got != "zh" {
+		t.Errorf("Expected zh variant, got %q", got)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected one upstream call per variant, got %d", calls.Load())
+	}
+
+	m.Invalidate("example.com/page")
+	serve(h, "/page", en)
+	serve(h, "/page", zh)
+	if calls.Load() != 4 {
+		t.Errorf("Expected Invalidate to drop every variant, got %d calls", calls.Load())
+	}
+}
+
+func TestMiddlewareTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header http.Header
+		cached bool
+	}{
+		{"no cache-control", http.StatusOK, nil, false},
+		{"no-store", http.StatusOK, http.Header{"Cache-Control": {"no-store"}}, false},
+		{"private", http.StatusOK, http.Header{"Cache-Control": {"private, max-age=60"}}, false},
+		{"set-cookie", http.StatusOK, http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"a=b"}}, false},
+		{"server error", http.StatusInternalServerError, http.Header{"Cache-Control": {"max-age=60"}}, false},
+		{"s-maxage", http.StatusOK, http.Header{"Cache-Control": {"s-maxage=60"}}, true},
+		{"expires", http.StatusNotFound, http.Header{"Expires": {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}}, true},
+	}
+	for _, tt := range tests {
+		var calls atomic.Int32
+		m := New(Options{})
+		h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			for name, values := range tt.header {
+				w.Header()[name] = values
+			}
+			w.WriteHeader(tt.status)
+		}))
+		serve(h, "/", nil)
+		serve(h, "/", nil)
+		if cached := calls.Load() == 1; cached != tt.cached {
+			t.Errorf("%s: expected cached %v, got %d upstream calls", tt.name, tt.cached, calls.Load())
+		}
+		m.Close()
+	}
+}
+
+func TestMiddlewareExpire(t *testing.T) {
+	var calls atomic.Int32
+	m := New(Options{DefaultTTL: 20 * time.Millisecond, MaxBodySize: 4})
+	defer m.Close()
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, r.URL.Path)
+	}))
+
+	serve(h, "/a", nil)
+	serve(h, "/a", nil)
+	if calls.Load() != 1 {
+		t.Errorf("Expected DefaultTTL to apply, got %d calls", calls.Load())
+	}
+	time.Sleep(30 * time.Millisecond)
+	serve(h, "/a", nil)
+	if calls.Load() != 2 {
+		t.Errorf("Expected expired response to miss, got %d calls", calls.Load())
+	}
+
+	serve(h, "/large", nil)
+	if got := serve(h, "/large", nil).Body.String(); got != "/large" || calls.Load() != 4 {
+		t.Errorf("Expected body over MaxBodySize not to be cached, got %q after %d calls", got, calls.Load())
+	}
+}
+
+func TestMiddlewareHost(t *testing.T) {
+	m := New(Options{})
+	defer m.Close()
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		fmt.Fprintf(w, "tenant %s", r.Host)
+	}))
+
+	// 不同的虚拟主机即使路径相同也不共享缓存的响应
+	serveHost := func(host string) string {
+		req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	serveHost("a.example.com")
+	if body := serveHost("b.example.com"); body != "tenant b.example.com" {
+		t.Errorf("Expected response for b.example.com, got %q", body)
+	}
+	if body := serveHost("a.example.com"); body != "tenant a.example.com" {
+		t.Errorf("Expected cached response for a.example.com, got %q", body)
+	}
+}
@@ -1,6 +1,16 @@
 package cachex
 
-import "time"
+import (
+	"container/list"
+	"errors"
+	"time"
+)
+
+// ErrNotFound 表示键对应的数据不存在，加载函数返回该错误时可以缓存这次未命中
+var ErrNotFound = errors.New("cachex: not found")
+
+// ErrExpired 表示键曾经缓存过值，但已经过了有效期
+var ErrExpired = errors.New("cachex: expired")
 
 type Cache[T any] interface {
 	Get(key string) (value T, ok bool)
@@ -9,8 +19,77 @@ type Cache[T any] interface {
 	Del(key string)
 }
 
-type cacheItemWrapper[T any] struct {
+// EvictReason 条目被移除的原因
+type EvictReason int
+
+const (
+	// EvictExpired 条目过期
+	EvictExpired EvictReason = iota
+	// EvictDeleted 手动删除
+	EvictDeleted
+	// EvictCapacity 超出容量被淘汰
+	EvictCapacity
+	// EvictDestroyed 缓存被销毁
+	EvictDestroyed
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictCapacity:
+		return "capacity"
+	case EvictDestroyed:
+		return "destroyed"
+	}
+	return "unknown"
+}
+
+type evictedEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+type cacheItemWrapper[K comparable, T any] struct {
+	key       K
 	value     T
-	expire    time.Time
+	created   time.Time // 写入时间
+	expire    time.Time // 过期时间，超过后条目不再新鲜
+	deadline  time.Time // 删除时间，允许返回旧值时晚于 expire
 	canExpire bool
+	elem      *list.Element // 在访问顺序链表中的位置
+	cost      int64
+	heapIndex int         // 在过期堆中的下标，不在堆中时为 -1
+	negative  bool        // 记录的是数据不存在，而不是一个值
+	pinned    bool        // 固定的条目不会过期也不会被容量淘汰，不在过期堆和访问顺序链表中
+	version   uint64      // 写入时分配的版本号，同一个缓存内单调递增
+	access    *itemAccess // 访问统计，读取可能只持有读锁，因此单独分配并原子更新
+	tags      []string    // 条目所属的标签，用于按标签批量失效
+}
+
+func newCacheItem[K comparable, T any](key K, value T, expire time.Duration, now time.Time) *cacheItemWrapper[K, T] {
+	t := now.Add(expire)
+	return &cacheItemWrapper[K, T]{
+		key:       key,
+		value:     value,
+		created:   now,
+		expire:    t,
+		deadline:  t,
+		canExpire: expire > 0,
+		heapIndex: -1,
+		access:    new(itemAccess),
+	}
+}
+
+// expired 判断条目在 now 时刻是否已经失效，失效的条目需要删除
+func (w *cacheItemWrapper[K, T]) expired(now time.Time) bool {
+	return w.canExpire && !w.pinned && !w.deadline.After(now)
+}
+
+// stale 判断条目在 now 时刻是否已经过了新鲜期
+func (w *cacheItemWrapper[K, T]) stale(now time.Time) bool {
+	return w.canExpire && !w.expire.After(now)
 }
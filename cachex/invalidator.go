@@ -0,0 +1,250 @@
+package cachex
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Invalidation 一条失效通知，Source 标识发布者，订阅者据此忽略自己发出的通知
+type Invalidation struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys,omitempty"`
+	// All 为 true 时表示清空所有键
+	All bool `json:"all,omitempty"`
+}
+
+// Invalidator 在多个副本之间传递失效通知，使各自的本地缓存保持一致
+type Invalidator interface {
+	Publish(ctx context.Context, msg Invalidation) error
+	// Subscribe 注册通知的处理函数，返回的 cancel 用于取消订阅
+	Subscribe(fn func(msg Invalidation)) (cancel func(), err error)
+}
+
+// invalidation 缓存在失效通知中的身份、订阅状态和发布队列
+type invalidation struct {
+	source string
+	mu     sync.Mutex
+	cancel func()
+
+	// queueMu 保护 queue 的关闭，发送时持有读锁
+	queueMu sync.RWMutex
+	queue   chan Invalidation
+	stopped bool
+	done    chan struct{}
+	// pending 已入队但尚未发布的通知数量
+	pending sync.WaitGroup
+}
+
+// stop 取消订阅，并等待队列中剩余的通知发布完成
+func (i *invalidation) stop() {
+	i.mu.Lock()
+	if i.cancel != nil {
+		i.cancel()
+		i.cancel = nil
+	}
+	i.mu.Unlock()
+
+	i.queueMu.Lock()
+	if i.queue == nil || i.stopped {
+		i.queueMu.Unlock()
+		return
+	}
+	i.stopped = true
+	close(i.queue)
+	i.queueMu.Unlock()
+	<-i.done
+}
+
+func (c *BaseCache[K, V]) subscribe() {
+	c.inval.source = strconv.FormatUint(rand.Uint64(), 36)
+	n := c.opts.InvalidateQueue
+	if n <= 0 {
+		n = 1024
+	}
+	c.inval.queue = make(chan Invalidation, n)
+	c.inval.done = make(chan struct{})
+	go c.runPublish()
+
+	cancel, err := c.opts.Invalidator.Subscribe(c.onInvalidation)
+	if err != nil {
+		c.onInvalidateError(err)
+		return
+	}
+	c.inval.mu.Lock()
+	c.inval.cancel = cancel
+	c.inval.mu.Unlock()
+}
+
+// onInvalidation 删除其他副本通知失效的键，删除不会再次发布通知
+func (c *BaseCache[K, V]) onInvalidation(msg Invalidation) {
+	if msg.Source == c.inval.source || c.closed.Load() {
+		return
+	}
+	if msg.All {
+		c.clearLocal()
+		return
+	}
+	for _, str := range msg.Keys {
+		key, ok := c.parseKey(str)
+		if !ok {
+			c.onInvalidateError(fmt.Errorf("cachex: cannot parse invalidated key %q", str))
+			continue
+		}
+		s := c.shard(key)
+		s.lock()
+		if item, ok := s.cache[key]; ok {
+			s.removeItem(key, item, EvictDeleted)
+		}
+		delete(s.stale, key)
+		s.unlock()
+	}
+}
+
+// publish 把通知放入发布队列，不等待发布完成，缓存关闭后不再发布
+func (c *BaseCache[K, V]) publish(msg Invalidation) {
+	if c.opts.Invalidator == nil {
+		return
+	}
+	c.inval.queueMu.RLock()
+	defer c.inval.queueMu.RUnlock()
+	if c.inval.stopped {
+		return
+	}
+	c.inval.pending.Add(1)
+	c.inval.queue <- msg
+}
+
+// runPublish 依次发布队列中的通知，已经排队的通知合并为一次发布
+func (c *BaseCache[K, V]) runPublish() {
+	defer close(c.inval.done)
+	timeout := c.opts.InvalidateTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	for msg := range c.inval.queue {
+		n := 1
+	merge:
+		for {
+			select {
+			case next, ok := <-c.inval.queue:
+				if !ok {
+					break merge
+				}
+				msg = mergeInvalidation(msg, next)
+				n++
+			default:
+				break merge
+			}
+		}
+		msg.Source = c.inval.source
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		c.onInvalidateError(c.opts.Invalidator.Publish(ctx, msg))
+		cancel()
+		c.inval.pending.Add(-n)
+	}
+}
+
+// mergeInvalidation 合并两条通知，任意一条清空所有键时结果也清空所有键
+func mergeInvalidation(a, b Invalidation) Invalidation {
+	if a.All || b.All {
+		return Invalidation{All: true}
+	}
+	a.Keys = append(a.Keys, b.Keys...)
+	return a
+}
+
+func (c *BaseCache[K, V]) onInvalidateError(err error) {
+	if err != nil && c.opts.OnInvalidateError != nil {
+		c.opts.OnInvalidateError(err)
+	}
+}
+
+// keyString 将键转换为字符串，用于 Store 和 Invalidator
+func (c *BaseCache[K, V]) keyString(key K) string {
+	if c.opts.StoreKey != nil {
+		return c.opts.StoreKey(key)
+	}
+	return fmt.Sprint(key)
+}
+
+// parseKey 将字符串还原为键，未设置 ParseKey 时只支持字符串和整数类型
+func (c *BaseCache[K, V]) parseKey(s string) (K, bool) {
+	if c.opts.ParseKey != nil {
+		return c.opts.ParseKey(s)
+	}
+	var key K
+	switch p := any(&key).(type) {
+	case *string:
+		*p = s
+	case *int:
+		n, err := strconv.Atoi(s)
+		*p = n
+		return key, err == nil
+	case *int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		*p = n
+		return key, err == nil
+	case *int32:
+		n, err := strconv.ParseInt(s, 10, 32)
+		*p = int32(n)
+		return key, err == nil
+	case *uint:
+		n, err := strconv.ParseUint(s, 10, 0)
+		*p = uint(n)
+		return key, err == nil
+	case *uint64:
+		n, err := strconv.ParseUint(s, 10, 64)
+		*p = n
+		return key, err == nil
+	case *uint32:
+		n, err := strconv.ParseUint(s, 10, 32)
+		*p = uint32(n)
+		return key, err == nil
+	default:
+		return key, false
+	}
+	return key, true
+}
+
+// LocalInvalidator 进程内的失效通知，同步调用所有订阅者，适合同一进程中的多个缓存或测试
+type LocalInvalidator struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]func(msg Invalidation)
+}
+
+var _ Invalidator = (*LocalInvalidator)(nil)
+
+func NewLocalInvalidator() *LocalInvalidator {
+	return &LocalInvalidator{subs: make(map[int]func(msg Invalidation))}
+}
+
+func (l *LocalInvalidator) Publish(ctx context.Context, msg Invalidation) error {
+	l.mu.RLock()
+	subs := make([]func(msg Invalidation), 0, len(l.subs))
+	for _, fn := range l.subs {
+		subs = append(subs, fn)
+	}
+	l.mu.RUnlock()
+	for _, fn := range subs {
+		fn(msg)
+	}
+	return nil
+}
+
+func (l *LocalInvalidator) Subscribe(fn func(msg Invalidation)) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id := l.nextID
+	l.nextID++
+	l.subs[id] = fn
+	return func() {
+		l.mu.Lock()
+		delete(l.subs, id)
+		l.mu.Unlock()
+	}, nil
+}
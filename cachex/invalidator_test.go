@@ -0,0 +1,127 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInvalidator(t *testing.T) {
+	bus := NewLocalInvalidator()
+	a := NewBaseCache(OnceCacheOption[int, string]{Invalidator: bus})
+	b := NewBaseCache(OnceCacheOption[int, string]{Invalidator: bus})
+	defer a.Close()
+	defer b.Close()
+
+	a.Set(1, "a1")
+	a.inval.pending.Wait()
+	b.Set(1, "b1")
+	b.inval.pending.Wait()
+	if _, ok := a.Get(1); ok {
+		t.Errorf("Expected write on b to invalidate a")
+	}
+	if v, ok := b.Get(1); !ok || v != "b1" {
+		t.Errorf("Expected b to keep its own write, got %v, ok: %v", v, ok)
+	}
+
+	a.Set(2, "a2")
+	a.Del(1)
+	a.inval.pending.Wait()
+	if _, ok := b.Get(1); ok {
+		t.Errorf("Expected Del on a to invalidate b")
+	}
+
+	b.MSet(map[int]string{2: "b2", 3: "b3"}, 0)
+	a.Set(4, "a4")
+	a.inval.pending.Wait()
+	b.Clear()
+	b.inval.pending.Wait()
+	if a.Len() != 0 {
+		t.Errorf("Expected Clear on b to clear a, got %d entries", a.Len())
+	}
+
+	// 销毁后取消订阅
+	a.Close()
+	if n := len(bus.subs); n != 1 {
+		t.Errorf("Expected closed cache to unsubscribe, got %d subscribers", n)
+	}
+}
+
+// blockingInvalidator 发布时一直阻塞到 ctx 结束
+type blockingInvalidator struct {
+	*LocalInvalidator
+}
+
+func (blockingInvalidator) Publish(ctx context.Context, msg Invalidation) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestInvalidatorAsync(t *testing.T) {
+	var publishes atomic.Int32
+	var timedOut atomic.Bool
+	cache := NewBaseCache(OnceCacheOption[int, string]{
+		Invalidator:       blockingInvalidator{NewLocalInvalidator()},
+		InvalidateTimeout: 10 * time.Millisecond,
+		OnInvalidateError: func(err error) {
+			publishes.Add(1)
+			if errors.Is(err, context.DeadlineExceeded) {
+				timedOut.Store(true)
+			}
+		},
+	})
+
+	// 消息中间件卡住时写入不会被阻塞
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		cache.Set(i, "v")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Expected writes not to wait for publishing, took %v", d)
+	}
+	// 关闭时发完剩余的通知，每次发布都受超时限制，排队的通知合并发布
+	cache.Close()
+	if !timedOut.Load() {
+		t.Errorf("Expected publish to time out")
+	}
+	if n := publishes.Load(); n < 1 || n >= 100 {
+		t.Errorf("Expected queued invalidations to be merged, got %d publishes", n)
+	}
+}
+
+func TestInvalidatorParseKey(t *testing.T) {
+	var errs []error
+	bus := NewLocalInvalidator()
+	a := NewBaseCache(OnceCacheOption[GroupKey, int]{
+		Invalidator: bus,
+		StoreKey: func(key GroupKey) string {
+			return key.Namespace + "/" + key.Key
+		},
+		OnInvalidateError: func(err error) {
+			errs = append(errs, err)
+		},
+	})
+	defer a.Close()
+	a.Set(GroupKey{"users", "1"}, 1)
+	a.inval.pending.Wait()
+	bus.Publish(context.Background(), Invalidation{Keys: []string{"users/1"}})
+	if len(errs) != 1 {
+		t.Errorf("Expected parse error without ParseKey, got %v", errs)
+	}
+
+	b := NewBaseCache(OnceCacheOption[GroupKey, int]{
+		Invalidator: bus,
+		ParseKey: func(s string) (GroupKey, bool) {
+			return GroupKey{"users", s[len("users/"):]}, true
+		},
+	})
+	defer b.Close()
+	b.Set(GroupKey{"users", "1"}, 1)
+	b.inval.pending.Wait()
+	bus.Publish(context.Background(), Invalidation{Keys: []string{"users/1"}})
+	if _, ok := b.Get(GroupKey{"users", "1"}); ok {
+		t.Errorf("Expected ParseKey to be used")
+	}
+}
@@ -0,0 +1,81 @@
+package cachex
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Key 将多个不同类型的部分序列化并哈希为稳定的键，相同的参数在不同进程中得到相同的结果
+// 每个部分都带有类型标记和长度前缀，不会出现 Key("a", "bc") 与 Key("ab", "c") 这样的冲突
+// 结构体、切片和 map 按 JSON 编码，map 的键按顺序排列，无法编码为 JSON 的值使用 %#v 格式
+func Key(parts ...any) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, part := range parts {
+		tag, data := encodeKeyPart(part)
+		binary.BigEndian.PutUint64(buf[:], uint64(len(data)))
+		h.Write([]byte{tag})
+		h.Write(buf[:])
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// encodeKeyPart 返回单个部分的类型标记和字节表示
+func encodeKeyPart(part any) (byte, []byte) {
+	switch v := part.(type) {
+	case nil:
+		return 'n', nil
+	case string:
+		return 's', []byte(v)
+	case []byte:
+		return 'b', v
+	case bool:
+		return 't', []byte(strconv.FormatBool(v))
+	case int:
+		return 'i', strconv.AppendInt(nil, int64(v), 10)
+	case int8:
+		return 'i', strconv.AppendInt(nil, int64(v), 10)
+	case int16:
+		return 'i', strconv.AppendInt(nil, int64(v), 10)
+	case int32:
+		return 'i', strconv.AppendInt(nil, int64(v), 10)
+	case int64:
+		return 'i', strconv.AppendInt(nil, v, 10)
+	case uint:
+		return 'u', strconv.AppendUint(nil, uint64(v), 10)
+	case uint8:
+		return 'u', strconv.AppendUint(nil, uint64(v), 10)
+	case uint16:
+		return 'u', strconv.AppendUint(nil, uint64(v), 10)
+	case uint32:
+		return 'u', strconv.AppendUint(nil, uint64(v), 10)
+	case uint64:
+		return 'u', strconv.AppendUint(nil, v, 10)
+	case float32:
+		return 'f', floatBytes(float64(v))
+	case float64:
+		return 'f', floatBytes(v)
+	case time.Time:
+		// 同一时刻在不同时区下得到相同的键
+		return 'T', []byte(v.UTC().Format(time.RFC3339Nano))
+	case time.Duration:
+		return 'd', strconv.AppendInt(nil, int64(v), 10)
+	}
+	if data, err := json.Marshal(part); err == nil {
+		return 'j', data
+	}
+	return 'v', []byte(fmt.Sprintf("%#v", part))
+}
+
+func floatBytes(f float64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(f))
+	return b
+}
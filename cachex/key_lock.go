@@ -0,0 +1,48 @@
+package cachex
+
+import "sync"
+
+// keyMutex 单个键的互斥锁，refs 记录持有和等待的数量
+type keyMutex struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// LockKey 锁定单个键，直到调用 UnlockKey，不同的键之间互不影响
+// 键锁只用于调用方之间的协调，不会阻塞缓存自身的读写
+func (c *BaseCache[K, V]) LockKey(key K) {
+	s := c.shard(key)
+	s.mu.Lock()
+	m, ok := s.keyLocks[key]
+	if !ok {
+		m = &keyMutex{}
+		s.keyLocks[key] = m
+	}
+	m.refs++
+	s.mu.Unlock()
+	m.mu.Lock()
+}
+
+// UnlockKey 释放 LockKey 锁定的键，键未被锁定时 panic
+func (c *BaseCache[K, V]) UnlockKey(key K) {
+	s := c.shard(key)
+	s.mu.Lock()
+	m, ok := s.keyLocks[key]
+	if !ok {
+		s.mu.Unlock()
+		panic("cachex: unlock of unlocked key")
+	}
+	if m.refs--; m.refs == 0 {
+		delete(s.keyLocks, key)
+	}
+	s.mu.Unlock()
+	m.mu.Unlock()
+}
+
+// WithKeyLock 在持有键锁的情况下执行 fn，可用于对单个键做读取、修改、写回
+// fn 中可以正常访问缓存，但不能再次锁定同一个键
+func (c *BaseCache[K, V]) WithKeyLock(key K, fn func()) {
+	c.LockKey(key)
+	defer c.UnlockKey(key)
+	fn()
+}
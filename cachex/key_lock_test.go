@@ -0,0 +1,59 @@
+package cachex
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithKeyLock(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, []int]{})
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache.WithKeyLock("list", func() {
+				list, _ := cache.Get("list")
+				cache.Set("list", append(list, i))
+			})
+		}(i)
+	}
+	wg.Wait()
+	if list, _ := cache.Get("list"); len(list) != 50 {
+		t.Errorf("Expected 50 items, got %d", len(list))
+	}
+	if n := len(cache.shards[0].keyLocks); n != 0 {
+		t.Errorf("Expected key locks to be released, got %d", n)
+	}
+}
+
+func TestLockKeyIndependent(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	cache.LockKey("a")
+	done := make(chan struct{})
+	go func() {
+		// 其他键和缓存读写不受键锁影响
+		cache.WithKeyLock("b", func() {
+			cache.Set("a", 1)
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected other keys not to be blocked")
+	}
+	cache.UnlockKey("a")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic when unlocking an unlocked key")
+		}
+	}()
+	cache.UnlockKey("a")
+}
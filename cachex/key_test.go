@@ -0,0 +1,52 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	type query struct {
+		Name  string
+		Limit int
+	}
+	k := Key("users", 42, query{Name: "alice", Limit: 10})
+	if k != Key("users", 42, query{Name: "alice", Limit: 10}) {
+		t.Errorf("Expected the same parts to produce the same key")
+	}
+	if len(k) != 32 {
+		t.Errorf("Expected 32 hex characters, got %q", k)
+	}
+
+	distinct := []string{
+		Key("a", "bc"),
+		Key("ab", "c"),
+		Key("1"),
+		Key(1),
+		Key(uint(1)),
+		Key(1.0),
+		Key(true),
+		Key(nil),
+		Key(),
+		Key(map[string]int{"a": 1, "b": 2}),
+	}
+	seen := make(map[string]int)
+	for i, key := range distinct {
+		if j, ok := seen[key]; ok {
+			t.Errorf("Expected distinct keys, %d and %d collide", i, j)
+		}
+		seen[key] = i
+	}
+
+	// map 按键排序编码，时间按 UTC 编码
+	if Key(map[string]int{"a": 1, "b": 2}) != Key(map[string]int{"b": 2, "a": 1}) {
+		t.Errorf("Expected map key order not to matter")
+	}
+	now := time.Now()
+	if Key(now) != Key(now.In(time.FixedZone("X", 3600))) {
+		t.Errorf("Expected the same instant to produce the same key")
+	}
+	if Key(int32(7)) != Key(int64(7)) {
+		t.Errorf("Expected integers of different widths with the same value to match")
+	}
+}
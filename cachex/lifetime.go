@@ -0,0 +1,69 @@
+package cachex
+
+import (
+	"sync"
+	"time"
+)
+
+// lifetime 缓存整体的生命周期，到期后调用 expire 销毁缓存
+type lifetime struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	deadline time.Time
+}
+
+func (l *lifetime) start(d time.Duration, expire func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.deadline = time.Now().Add(d)
+	l.timer = time.AfterFunc(d, expire)
+}
+
+// reset 将到期时间修改为 deadline，计时器已经触发时返回 false
+func (l *lifetime) reset(deadline func(old time.Time) time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.timer == nil || !l.timer.Stop() {
+		return false
+	}
+	l.deadline = deadline(l.deadline)
+	l.timer.Reset(time.Until(l.deadline))
+	return true
+}
+
+func (l *lifetime) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
+
+// Deadline 返回缓存整体的到期时间，没有设置 Expire 时 ok 为 false
+func (c *BaseCache[K, V]) Deadline() (deadline time.Time, ok bool) {
+	c.life.mu.Lock()
+	defer c.life.mu.Unlock()
+	return c.life.deadline, c.life.timer != nil
+}
+
+// ExtendLifetime 将缓存整体的到期时间推迟 d，可用于让运行时间超出预期的任务继续使用缓存
+// 没有设置 Expire 或缓存已经销毁时返回 false
+func (c *BaseCache[K, V]) ExtendLifetime(d time.Duration) bool {
+	if c.closed.Load() {
+		return false
+	}
+	return c.life.reset(func(old time.Time) time.Time {
+		return old.Add(d)
+	})
+}
+
+// ResetLifetime 从现在起重新计算缓存整体的生命周期，时长仍为 Expire
+// 没有设置 Expire 或缓存已经销毁时返回 false
+func (c *BaseCache[K, V]) ResetLifetime() bool {
+	if c.closed.Load() {
+		return false
+	}
+	return c.life.reset(func(time.Time) time.Time {
+		return time.Now().Add(c.opts.Expire)
+	})
+}
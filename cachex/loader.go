@@ -0,0 +1,97 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Loader 从数据源加载缓存值，LoadAll 返回的结果中缺少的键视为不存在
+type Loader[K comparable, V any] interface {
+	Load(ctx context.Context, key K) (V, error)
+	LoadAll(ctx context.Context, keys []K) (map[K]V, error)
+}
+
+// LoaderFunc 将单个键的加载函数适配为 Loader，LoadAll 依次加载每个键
+type LoaderFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+func (f LoaderFunc[K, V]) Load(ctx context.Context, key K) (V, error) {
+	return f(ctx, key)
+}
+
+// LoadAll 依次加载每个键，返回 ErrNotFound 的键被跳过，其他错误立即返回
+func (f LoaderFunc[K, V]) LoadAll(ctx context.Context, keys []K) (map[K]V, error) {
+	result := make(map[K]V, len(keys))
+	for _, key := range keys {
+		value, err := f(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// errNoLoader 没有配置加载器时 Preload 返回的错误
+var errNoLoader = errors.New("cachex: Preload requires BulkLoader or Loader")
+
+// Preload 按批次并发加载 keys 并写入缓存，可在服务开始接收请求前预热关键数据
+// 批次大小和并发数由 PreloadBatch 和 PreloadParallelism 控制，返回第一个失败批次的错误
+// 失败的批次不影响其他批次写入缓存
+func (c *BaseCache[K, V]) Preload(ctx context.Context, keys []K) error {
+	loader := c.opts.BulkLoader
+	if loader == nil && c.opts.Loader != nil {
+		loader = LoaderFunc[K, V](c.opts.Loader)
+	}
+	if loader == nil {
+		return errNoLoader
+	}
+	batch := c.opts.PreloadBatch
+	if batch <= 0 {
+		batch = 100
+	}
+	parallelism := c.opts.PreloadParallelism
+	if parallelism <= 0 {
+		parallelism = 4
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, parallelism)
+	)
+	for start := 0; start < len(keys); start += batch {
+		end := start + batch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func(keys []K) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.stats.loads.Add(1)
+			values, err := loader.LoadAll(ctx, keys)
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err != nil {
+				c.stats.loadFailures.Add(1)
+				once.Do(func() { firstErr = err })
+				return
+			}
+			c.mset(values, c.defaultExpire)
+		}(keys[start:end])
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,116 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLoader 记录批次数和最大并发数的批量加载器
+type countingLoader struct {
+	batches atomic.Int32
+	running atomic.Int32
+	peak    atomic.Int32
+	fail    int
+}
+
+func (l *countingLoader) Load(ctx context.Context, key int) (string, error) {
+	return strconv.Itoa(key), nil
+}
+
+func (l *countingLoader) LoadAll(ctx context.Context, keys []int) (map[int]string, error) {
+	l.batches.Add(1)
+	n := l.running.Add(1)
+	defer l.running.Add(-1)
+	for {
+		peak := l.peak.Load()
+		if n <= peak || l.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	result := make(map[int]string)
+	for _, key := range keys {
+		if key == l.fail {
+			return nil, errors.New("load failed")
+		}
+		result[key] = strconv.Itoa(key)
+	}
+	return result, nil
+}
+
+func TestPreload(t *testing.T) {
+	loader := &countingLoader{fail: -1}
+	cache := NewBaseCache(OnceCacheOption[int, string]{
+		BulkLoader:         loader,
+		PreloadBatch:       10,
+		PreloadParallelism: 3,
+	})
+	defer cache.Close()
+
+	keys := make([]int, 95)
+	for i := range keys {
+		keys[i] = i
+	}
+	if err := cache.Preload(context.Background(), keys); err != nil {
+		t.Fatalf("Preload failed: %v", err)
+	}
+	if n := cache.Len(); n != 95 {
+		t.Errorf("Expected 95 entries, got %d", n)
+	}
+	if n := loader.batches.Load(); n != 10 {
+		t.Errorf("Expected 10 batches, got %d", n)
+	}
+	if n := loader.peak.Load(); n > 3 {
+		t.Errorf("Expected at most 3 concurrent batches, got %d", n)
+	}
+	// BulkLoader 的 Load 同时作为缓存的 Loader
+	if cache.opts.Loader == nil {
+		t.Errorf("Expected BulkLoader.Load to be used as Loader")
+	}
+}
+
+func TestPreloadError(t *testing.T) {
+	loader := &countingLoader{fail: 15}
+	cache := NewBaseCache(OnceCacheOption[int, string]{BulkLoader: loader, PreloadBatch: 10})
+	defer cache.Close()
+
+	keys := make([]int, 30)
+	for i := range keys {
+		keys[i] = i
+	}
+	if err := cache.Preload(context.Background(), keys); err == nil {
+		t.Errorf("Expected error from failed batch")
+	}
+	if n := cache.Len(); n != 20 {
+		t.Errorf("Expected other batches to be cached, got %d", n)
+	}
+
+	empty := NewBaseCache(OnceCacheOption[int, string]{})
+	defer empty.Close()
+	if err := empty.Preload(context.Background(), keys); err == nil {
+		t.Errorf("Expected error without loader")
+	}
+}
+
+func TestLoaderFunc(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, string]{
+		Loader: func(ctx context.Context, key int) (string, error) {
+			if key%2 == 1 {
+				return "", ErrNotFound
+			}
+			return strconv.Itoa(key), nil
+		},
+	})
+	defer cache.Close()
+
+	if err := cache.Preload(context.Background(), []int{1, 2, 3, 4}); err != nil {
+		t.Fatalf("Preload failed: %v", err)
+	}
+	if keys := cache.Len(); keys != 2 {
+		t.Errorf("Expected missing keys to be skipped, got %d entries", keys)
+	}
+}
@@ -0,0 +1,197 @@
+package cachex
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRUCache 容量固定的协程安全 LRU 缓存，所有操作均为 O(1)
+type LRUCache[K comparable, V any] struct {
+	mu      sync.Mutex
+	items   map[K]*list.Element
+	ll      *list.List // 队首为最近访问
+	opts    LRUCacheOption[K, V]
+	evicted []evictedEntry[K, V] // 等待通知 OnEvict 的条目，释放锁后统一回调
+}
+
+type LRUCacheOption[K comparable, V any] struct {
+	// Capacity 最大条目数，小于等于0时不限制
+	Capacity int
+	// DefaultExpire 使用 Set 写入的条目的有效期，小于等于0时永不过期
+	DefaultExpire time.Duration
+	// OnEvict 条目被移除时的回调，在释放锁之后调用
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expire    time.Time
+	canExpire bool
+}
+
+func (e *lruEntry[K, V]) expired(now time.Time) bool {
+	return e.canExpire && !e.expire.After(now)
+}
+
+// NewLRUCache 创建一个 LRU 缓存
+func NewLRUCache[K comparable, V any](opts LRUCacheOption[K, V]) *LRUCache[K, V] {
+	return &LRUCache[K, V]{
+		items: make(map[K]*list.Element),
+		ll:    list.New(),
+		opts:  opts,
+	}
+}
+
+// Get 获取键对应的值，并将其标记为最近访问
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.ll.MoveToFront(c.items[key])
+	return entry.value, true
+}
+
+// Peek 获取键对应的值，不改变访问顺序
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set 添加或更新键值对，使用 DefaultExpire 作为有效期
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.SetExpire(key, value, c.opts.DefaultExpire)
+}
+
+// SetExpire 添加或更新键值对，并指定有效期，小于等于0时永不过期
+func (c *LRUCache[K, V]) SetExpire(key K, value V, expire time.Duration) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	entry := &lruEntry[K, V]{
+		key:       key,
+		value:     value,
+		expire:    time.Now().Add(expire),
+		canExpire: expire > 0,
+	}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.ll.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.ll.PushFront(entry)
+	c.shrink(c.opts.Capacity)
+}
+
+// Remove 删除键值对，返回键是否存在
+func (c *LRUCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(elem, EvictDeleted)
+	return true
+}
+
+// Resize 修改容量，返回因容量缩小而被淘汰的条目数
+func (c *LRUCache[K, V]) Resize(capacity int) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.opts.Capacity = capacity
+	return c.shrink(capacity)
+}
+
+// Len 返回条目数，包含尚未清理的过期条目
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Keys 按从新到旧的访问顺序返回所有未过期的键
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	keys := make([]K, 0, c.ll.Len())
+	for elem := c.ll.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*lruEntry[K, V])
+		if !entry.expired(now) {
+			keys = append(keys, entry.key)
+		}
+	}
+	return keys
+}
+
+// Clear 清空缓存，每个条目都会触发 OnEvict
+func (c *LRUCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.unlock()
+
+	for elem := c.ll.Back(); elem != nil; elem = c.ll.Back() {
+		c.removeElement(elem, EvictDeleted)
+	}
+}
+
+// unlock 释放锁，并通知持有锁期间被移除的条目
+func (c *LRUCache[K, V]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+	for _, e := range evicted {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// lookup 查找未过期的条目，过期的条目会被删除
+func (c *LRUCache[K, V]) lookup(key K) (*lruEntry[K, V], bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry[K, V])
+	if entry.expired(time.Now()) {
+		c.removeElement(elem, EvictExpired)
+		return nil, false
+	}
+	return entry, true
+}
+
+// shrink 淘汰最久未访问的条目直到不超过 capacity
+func (c *LRUCache[K, V]) shrink(capacity int) int {
+	if capacity <= 0 {
+		return 0
+	}
+	n := 0
+	for c.ll.Len() > capacity {
+		c.removeElement(c.ll.Back(), EvictCapacity)
+		n++
+	}
+	return n
+}
+
+func (c *LRUCache[K, V]) removeElement(elem *list.Element, reason EvictReason) {
+	entry := c.ll.Remove(elem).(*lruEntry[K, V])
+	delete(c.items, entry.key)
+	if c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evictedEntry[K, V]{key: entry.key, value: entry.value, reason: reason})
+	}
+}
@@ -0,0 +1,92 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	var evicted []string
+	cache := NewLRUCache(LRUCacheOption[string, int]{
+		Capacity: 2,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			evicted = append(evicted, key+":"+reason.String())
+		},
+	})
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	// Peek 不改变访问顺序，a 仍然是最久未访问的
+	if v, ok := cache.Peek("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+	cache.Set("c", 3)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected 'a' to be evicted")
+	}
+
+	// Get 会将 b 标记为最近访问
+	cache.Get("b")
+	cache.Set("d", 4)
+	if _, ok := cache.Get("c"); ok {
+		t.Errorf("Expected 'c' to be evicted")
+	}
+	keys := cache.Keys()
+	if len(keys) != 2 || keys[0] != "d" || keys[1] != "b" {
+		t.Errorf("Expected [d b], got %v", keys)
+	}
+
+	// 更新已存在的键
+	cache.Set("b", 20)
+	if v, _ := cache.Get("b"); v != 20 {
+		t.Errorf("Expected 20, got %v", v)
+	}
+
+	if !cache.Remove("b") || cache.Remove("b") {
+		t.Errorf("Expected Remove to report existence")
+	}
+
+	cache.Resize(3)
+	cache.Set("e", 5)
+	cache.Set("f", 6)
+	if cache.Len() != 3 {
+		t.Errorf("Expected 3 entries, got %d", cache.Len())
+	}
+	if n := cache.Resize(1); n != 2 {
+		t.Errorf("Expected 2 entries evicted by Resize, got %d", n)
+	}
+	if _, ok := cache.Get("f"); !ok {
+		t.Errorf("Expected most recent 'f' to be kept")
+	}
+
+	expected := []string{"a:capacity", "c:capacity", "b:deleted", "d:capacity", "e:capacity"}
+	if len(evicted) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, evicted)
+	}
+	for i, e := range expected {
+		if evicted[i] != e {
+			t.Errorf("Expected %v, got %v", expected, evicted)
+			break
+		}
+	}
+}
+
+func TestLRUCacheExpire(t *testing.T) {
+	cache := NewLRUCache(LRUCacheOption[string, int]{
+		Capacity:      10,
+		DefaultExpire: 10 * time.Millisecond,
+	})
+	cache.Set("a", 1)
+	cache.SetExpire("b", 2, 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected 'a' to be expired")
+	}
+	if v, ok := cache.Peek("b"); !ok || v != 2 {
+		t.Errorf("Expected 2, got %v, ok: %v", v, ok)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected expired entry to be removed on access, got %d", cache.Len())
+	}
+}
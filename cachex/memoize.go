@@ -0,0 +1,111 @@
+package cachex
+
+import (
+	"sync"
+	"time"
+)
+
+// Memoize 返回带缓存的 fn，相同参数在 ttl 内只会计算一次，并发的相同调用会合并为一次
+// ttl 小于等于0时结果永久缓存，过期的结果在读取时重新计算，不启动后台协程，不再使用时无需释放
+// fn 发生 panic 时结果不会被缓存，等待同一次调用的其他调用方会各自重新调用 fn
+func Memoize[A comparable, R any](fn func(A) R, ttl time.Duration) func(A) R {
+	var mu sync.Mutex
+	calls := make(map[A]*memoCall[R])
+	pruneAt := memoPruneMin
+	return func(a A) R {
+		now := time.Now()
+		mu.Lock()
+		if call, ok := calls[a]; ok && !call.expired(ttl, now) {
+			mu.Unlock()
+			<-call.done
+			if !call.ok {
+				return fn(a)
+			}
+			return call.value
+		}
+		call := &memoCall[R]{done: make(chan struct{})}
+		calls[a] = call
+		if len(calls) >= pruneAt {
+			// 结果数量每翻一倍清理一次过期的结果，不再被调用的参数也不会一直占用内存
+			for key, c := range calls {
+				if c.expired(ttl, now) {
+					delete(calls, key)
+				}
+			}
+			pruneAt = max(2*len(calls), memoPruneMin)
+		}
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			if !call.ok && calls[a] == call {
+				delete(calls, a)
+			}
+			mu.Unlock()
+			close(call.done)
+		}()
+		value := fn(a)
+		mu.Lock()
+		call.value, call.ok, call.at = value, true, time.Now()
+		mu.Unlock()
+		return value
+	}
+}
+
+// memoPruneMin Memoize 第一次清理过期结果时的结果数量
+const memoPruneMin = 64
+
+// memoCall Memoize 中一次计算的结果，计算完成前 ok 为 false，等待者在 done 上等待
+type memoCall[R any] struct {
+	done  chan struct{}
+	value R
+	ok    bool
+	at    time.Time // 计算完成的时间
+}
+
+// expired 需在持有锁的情况下调用，正在计算的结果不会过期
+func (c *memoCall[R]) expired(ttl time.Duration, now time.Time) bool {
+	return c.ok && ttl > 0 && !now.Before(c.at.Add(ttl))
+}
+
+// MemoizeWithStop 与 Memoize 相同，但结果保存在缓存中，ttl 大于0时后台按 ttl 的间隔清理过期结果
+// 不再使用时需要调用 stop 释放缓存和后台协程，stop 之后 memo 每次都会直接调用 fn
+func MemoizeWithStop[A comparable, R any](fn func(A) R, ttl time.Duration) (memo func(A) R, stop func()) {
+	cache := NewBaseCache(OnceCacheOption[A, R]{
+		DefaultKeyExpire: ttl,
+		CheckInterval:    ttl,
+	})
+	memo = func(a A) R {
+		return cache.GetOrSetFunc(a, func() R {
+			return fn(a)
+		})
+	}
+	return memo, cache.Close
+}
+
+// memoKey2 Memoize2 的组合键
+type memoKey2[A, B comparable] struct {
+	a A
+	b B
+}
+
+// Memoize2 与 Memoize 相同，用于两个参数的函数
+func Memoize2[A, B comparable, R any](fn func(A, B) R, ttl time.Duration) func(A, B) R {
+	memo := Memoize(func(k memoKey2[A, B]) R {
+		return fn(k.a, k.b)
+	}, ttl)
+	return func(a A, b B) R {
+		return memo(memoKey2[A, B]{a, b})
+	}
+}
+
+// Memoize2WithStop 与 MemoizeWithStop 相同，用于两个参数的函数
+func Memoize2WithStop[A, B comparable, R any](fn func(A, B) R, ttl time.Duration) (memo func(A, B) R, stop func()) {
+	memo1, stop := MemoizeWithStop(func(k memoKey2[A, B]) R {
+		return fn(k.a, k.b)
+	}, ttl)
+	memo = func(a A, b B) R {
+		return memo1(memoKey2[A, B]{a, b})
+	}
+	return memo, stop
+}
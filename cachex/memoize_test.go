@@ -0,0 +1,116 @@
+package cachex
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	var calls atomic.Int32
+	square := Memoize(func(n int) int {
+		calls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		return n * n
+	}, 20*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := square(3); v != 9 {
+				t.Errorf("Expected 9, got %d", v)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected concurrent calls to be merged, got %d", n)
+	}
+	square(4)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected a new argument to be computed, got %d", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	square(3)
+	if n := calls.Load(); n != 3 {
+		t.Errorf("Expected result to be recomputed after ttl, got %d", n)
+	}
+}
+
+func TestMemoize2(t *testing.T) {
+	var calls int
+	add, stop := Memoize2WithStop(func(a int, b string) string {
+		calls++
+		return b + string(rune('0'+a))
+	}, 0)
+	defer stop()
+
+	if v := add(1, "x"); v != "x1" {
+		t.Errorf("Expected x1, got %v", v)
+	}
+	add(1, "x")
+	add(2, "x")
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+
+	stop()
+	if v := add(1, "x"); v != "x1" || calls != 3 {
+		t.Errorf("Expected fn to be called directly after stop, got %v with %d calls", v, calls)
+	}
+}
+
+func TestMemoizeStop(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		double, stop := MemoizeWithStop(func(n int) int { return n * 2 }, time.Millisecond)
+		double(i)
+		stop()
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected stop to release background goroutines, got %d -> %d", before, after)
+	}
+}
+
+func TestMemoizeNoGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		double := Memoize(func(n int) int { return n * 2 }, time.Millisecond)
+		double(i)
+		add := Memoize2(func(a, b int) int { return a + b }, 0)
+		add(i, i)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected Memoize not to start goroutines, got %d -> %d", before, after)
+	}
+}
+
+func TestMemoizePanic(t *testing.T) {
+	var calls int
+	fail := true
+	f := Memoize(func(n int) int {
+		calls++
+		if fail {
+			panic("boom")
+		}
+		return n
+	}, 0)
+	func() {
+		defer func() { recover() }()
+		f(1)
+	}()
+	// panic 的结果不会被缓存
+	fail = false
+	if v := f(1); v != 1 || calls != 2 {
+		t.Errorf("Expected fn to be called again after panic, got %d with %d calls", v, calls)
+	}
+	f(1)
+	if calls != 2 {
+		t.Errorf("Expected result to be cached, got %d calls", calls)
+	}
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsSource 能够提供统计数据的缓存
+type StatsSource interface {
+	Stats() cachex.CacheStats
+}
+
+// Collector 将多个缓存的统计数据导出为 prometheus 指标，以 cache 标签区分
+type Collector struct {
+	mu     sync.RWMutex
+	caches map[string]StatsSource
+
+	hitRatio     *prometheus.Desc
+	entries      *prometheus.Desc
+	evictions    *prometheus.Desc
+	loadDuration *prometheus.HistogramVec
+}
+
+// NewCollector 创建一个收集器，namespace 作为所有指标名称的前缀
+func NewCollector(namespace string) *Collector {
+	labels := []string{"cache"}
+	return &Collector{
+		caches: make(map[string]StatsSource),
+		hitRatio: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "hit_ratio"),
+			"Ratio of cache hits to total lookups.",
+			labels, nil,
+		),
+		entries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "entries"),
+			"Number of entries currently held by the cache.",
+			labels, nil,
+		),
+		evictions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+			"Total number of entries evicted by expiry or capacity.",
+			labels, nil,
+		),
+		loadDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "load_duration_seconds",
+			Help:      "Duration of cache loader calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+}
+
+// Register 以 name 为标签登记一个缓存，重复登记会覆盖之前的缓存
+func (c *Collector) Register(name string, src StatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caches[name] = src
+}
+
+// Unregister 移除登记的缓存
+func (c *Collector) Unregister(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.caches, name)
+	c.loadDuration.DeleteLabelValues(name)
+}
+
+// ObserveLoad 记录一次加载耗时，通常在 OnceCacheOption.OnLoad 中调用
+func (c *Collector) ObserveLoad(name string, duration time.Duration) {
+	c.loadDuration.WithLabelValues(name).Observe(duration.Seconds())
+}
+
+// Describe 实现 prometheus.Collector 接口
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hitRatio
+	ch <- c.entries
+	ch <- c.evictions
+	c.loadDuration.Describe(ch)
+}
+
+// Collect 实现 prometheus.Collector 接口
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for name, src := range c.caches {
+		stats := src.Stats()
+		ch <- prometheus.MustNewConstMetric(c.hitRatio, prometheus.GaugeValue, stats.HitRatio(), name)
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(stats.Size), name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), name)
+	}
+	c.loadDuration.Collect(ch)
+}
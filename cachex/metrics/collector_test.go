@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector("app")
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		MaxEntries: 1,
+		OnLoad: func(key string, duration time.Duration, err error) {
+			collector.ObserveLoad("users", duration)
+		},
+	})
+	defer cache.Close()
+	collector.Register("users", cache)
+
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.GetOrSetFunc("b", func() int { return 2 })
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	expected := `
+# HELP app_cache_entries Number of entries currently held by the cache.
+# TYPE app_cache_entries gauge
+app_cache_entries{cache="users"} 1
+# HELP app_cache_evictions_total Total number of entries evicted by expiry or capacity.
+# TYPE app_cache_evictions_total counter
+app_cache_evictions_total{cache="users"} 1
+# HELP app_cache_hit_ratio Ratio of cache hits to total lookups.
+# TYPE app_cache_hit_ratio gauge
+app_cache_hit_ratio{cache="users"} 0.5
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"app_cache_entries", "app_cache_evictions_total", "app_cache_hit_ratio")
+	if err != nil {
+		t.Errorf("Unexpected metrics: %v", err)
+	}
+	if n := testutil.CollectAndCount(collector, "app_cache_load_duration_seconds"); n != 1 {
+		t.Errorf("Expected 1 load duration series, got %d", n)
+	}
+
+	collector.Unregister("users")
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("Expected no metrics after Unregister, got %d", n)
+	}
+}
@@ -0,0 +1,171 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PeerCache 多个节点共同分担加载的分布式缓存
+// 未命中的键按一致性哈希交给负责它的节点加载，负责的节点只加载一次并缓存结果，其他节点向它请求
+// 从其他节点取回的值按 HotRatio 的概率复制到本地的热点缓存，访问频繁的键不必每次都跨节点请求
+type PeerCache[V any] struct {
+	opts PeerCacheOption[V]
+	main *BaseCache[string, V] // 本节点负责的键
+	hot  *BaseCache[string, V] // 其他节点负责的热点键的副本
+
+	mu      sync.Mutex
+	flights map[string]*flightCall[V] // 正在向其他节点请求的键
+}
+
+type PeerCacheOption[V any] struct {
+	// Peers 集群中的节点，为空时所有键都由本节点加载
+	Peers *Peers
+	// Loader 加载本节点负责的键，返回 ErrNotFound 时其他节点也会得到 ErrNotFound
+	Loader func(ctx context.Context, key string) (V, error)
+	// Cache 本节点负责的键使用的缓存配置，其中的 Loader 会被忽略
+	Cache OnceCacheOption[string, V]
+	// HotCache 热点副本使用的缓存配置，没有设置容量时最多保存1024个键，没有设置有效期时副本1分钟后过期
+	HotCache OnceCacheOption[string, V]
+	// HotRatio 从其他节点取回的值写入热点缓存的概率，小于等于0时为0.1，大于等于1时总是写入
+	HotRatio float64
+	// Codec 节点之间传输值的编码方式，为空时使用 JSONCodec
+	Codec Codec
+}
+
+// NewPeerCache 创建分布式缓存
+func NewPeerCache[V any](opts PeerCacheOption[V]) *PeerCache[V] {
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec{}
+	}
+	if opts.HotRatio <= 0 {
+		opts.HotRatio = 0.1
+	}
+	if opts.HotCache.MaxEntries <= 0 && opts.HotCache.MaxCost <= 0 && opts.HotCache.MaxMemory <= 0 {
+		opts.HotCache.MaxEntries = 1024
+	}
+	if opts.HotCache.DefaultKeyExpire <= 0 {
+		opts.HotCache.DefaultKeyExpire = time.Minute
+	}
+	opts.Cache.Loader, opts.Cache.BulkLoader = nil, nil
+	return &PeerCache[V]{
+		opts:    opts,
+		main:    NewBaseCache(opts.Cache),
+		hot:     NewBaseCache(opts.HotCache),
+		flights: make(map[string]*flightCall[V]),
+	}
+}
+
+// Get 获取键对应的值，依次查找本地缓存、热点副本，都未命中时交给负责的节点加载
+// 负责的节点请求失败时退回到本节点加载，结果不写入缓存
+func (c *PeerCache[V]) Get(ctx context.Context, key string) (V, error) {
+	if value, ok := c.main.Get(key); ok {
+		return value, nil
+	}
+	if value, ok := c.hot.Get(key); ok {
+		return value, nil
+	}
+	var peer PeerGetter
+	var remote bool
+	if c.opts.Peers != nil {
+		peer, remote = c.opts.Peers.Pick(key)
+	}
+	if !remote {
+		return c.main.GetOrSetFuncCtx(ctx, key, func(ctx context.Context) (V, error) {
+			return c.opts.Loader(ctx, key)
+		})
+	}
+
+	c.mu.Lock()
+	if call, ok := c.flights[key]; ok {
+		c.mu.Unlock()
+		return call.wait(ctx)
+	}
+	call := newFlightCall[V]()
+	c.flights[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		// fetch 中退回本节点的 Loader 可能 panic，等待者需要同样收到错误
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("cache loader panic: %v", r)
+			c.release(key, call)
+			panic(r)
+		}
+	}()
+	call.value, call.err = c.fetch(ctx, peer, key)
+	c.release(key, call)
+	return call.value, call.err
+}
+
+// release 结束一次向其他节点的请求并唤醒等待者
+func (c *PeerCache[V]) release(key string, call *flightCall[V]) {
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// fetch 向负责的节点请求键，成功时按概率写入热点缓存
+func (c *PeerCache[V]) fetch(ctx context.Context, peer PeerGetter, key string) (V, error) {
+	var value V
+	data, err := peer.Get(ctx, key)
+	if err == nil {
+		if err = c.opts.Codec.Unmarshal(data, &value); err == nil {
+			if c.opts.HotRatio >= 1 || rand.Float64() < c.opts.HotRatio {
+				c.hot.Set(key, value)
+			}
+			return value, nil
+		}
+	}
+	if errors.Is(err, ErrNotFound) || ctx.Err() != nil {
+		return value, err
+	}
+	return c.opts.Loader(ctx, key)
+}
+
+// Load 由本节点加载键并返回编码后的值，供其他节点通过 PeerGetter 调用，不会再转发给其他节点
+func (c *PeerCache[V]) Load(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.main.GetOrSetFuncCtx(ctx, key, func(ctx context.Context) (V, error) {
+		return c.opts.Loader(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.opts.Codec.Marshal(value)
+}
+
+// ServeHTTP 以 HTTP 接口提供 Load，键通过查询参数 key 传递，键不存在时返回 404
+// 与 HTTPPeer 配合使用，每个 PeerCache 挂载在独立的路径上
+func (c *PeerCache[V]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := c.Load(r.Context(), r.URL.Query().Get("key"))
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Write(data)
+	}
+}
+
+// Del 删除本节点上该键的缓存和热点副本，其他节点上的副本在过期后失效
+func (c *PeerCache[V]) Del(key string) {
+	c.main.Del(key)
+	c.hot.Del(key)
+}
+
+// Close 销毁本地缓存和热点副本
+func (c *PeerCache[V]) Close() {
+	c.main.Close()
+	c.hot.Close()
+}
+
+// Stats 分别返回本节点负责的键和热点副本的统计数据
+func (c *PeerCache[V]) Stats() (main, hot CacheStats) {
+	return c.main.Stats(), c.hot.Stats()
+}
@@ -0,0 +1,193 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newPeerCluster 创建互相连接的多个节点，每个节点的加载次数记录在 loads 中
+func newPeerCluster(t *testing.T, names []string, hotRatio float64) (map[string]*PeerCache[string], map[string]*atomic.Int32) {
+	caches := make(map[string]*PeerCache[string])
+	loads := make(map[string]*atomic.Int32)
+	for _, name := range names {
+		name := name
+		loads[name] = new(atomic.Int32)
+		caches[name] = NewPeerCache(PeerCacheOption[string]{
+			Peers:    NewPeers(name, 0),
+			HotRatio: hotRatio,
+			Loader: func(ctx context.Context, key string) (string, error) {
+				loads[name].Add(1)
+				if key == "missing" {
+					return "", ErrNotFound
+				}
+				return name + ":" + key, nil
+			},
+		})
+		t.Cleanup(caches[name].Close)
+	}
+	for _, name := range names {
+		getters := make(map[string]PeerGetter)
+		for _, other := range names {
+			if other != name {
+				getters[other] = PeerGetterFunc(caches[other].Load)
+			}
+		}
+		caches[name].opts.Peers.Set(getters)
+	}
+	return caches, loads
+}
+
+func TestPeerCache(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	caches, loads := newPeerCluster(t, names, 0.000001)
+
+	// 每个键只由负责的节点加载一次，所有节点得到相同的值
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprint("key", i)
+		owner := caches["a"].opts.Peers.Owner(key)
+		for _, name := range names {
+			v, err := caches[name].Get(context.Background(), key)
+			if err != nil || v != owner+":"+key {
+				t.Fatalf("Expected %q from owner, got %q, err: %v", owner+":"+key, v, err)
+			}
+		}
+	}
+	var total int32
+	for _, name := range names {
+		total += loads[name].Load()
+	}
+	if total != 30 {
+		t.Errorf("Expected each key to be loaded once across the cluster, got %d loads", total)
+	}
+
+	for _, name := range names {
+		if _, err := caches[name].Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound on %q, got %v", name, err)
+		}
+	}
+}
+
+func TestPeerCacheHotReplication(t *testing.T) {
+	caches, _ := newPeerCluster(t, []string{"a", "b"}, 1)
+	var key string
+	for i := 0; ; i++ {
+		if key = fmt.Sprint("key", i); caches["a"].opts.Peers.Owner(key) == "b" {
+			break
+		}
+	}
+	if _, err := caches["a"].Get(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if _, hot := caches["a"].Stats(); hot.Size != 1 {
+		t.Errorf("Expected remote value to be replicated locally, got %d hot entries", hot.Size)
+	}
+	if main, _ := caches["a"].Stats(); main.Size != 0 {
+		t.Errorf("Expected remote value not to be stored as owned, got %d entries", main.Size)
+	}
+}
+
+func TestPeerCacheConcurrentRemote(t *testing.T) {
+	var remoteCalls atomic.Int32
+	release := make(chan struct{})
+	cache := NewPeerCache(PeerCacheOption[string]{
+		Peers: NewPeers("a", 0),
+		Loader: func(ctx context.Context, key string) (string, error) {
+			return "local", nil
+		},
+	})
+	defer cache.Close()
+	cache.opts.Peers.Set(map[string]PeerGetter{"b": PeerGetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		remoteCalls.Add(1)
+		<-release
+		return []byte(`"remote"`), nil
+	})})
+	var key string
+	for i := 0; ; i++ {
+		if key = fmt.Sprint("key", i); cache.opts.Peers.Owner(key) == "b" {
+			break
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cache.Get(context.Background(), key); err != nil || v != "remote" {
+				t.Errorf("Expected remote value, got %q, err: %v", v, err)
+			}
+		}()
+	}
+	for remoteCalls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if n := remoteCalls.Load(); n != 1 {
+		t.Errorf("Expected concurrent misses to share one remote request, got %d", n)
+	}
+}
+
+func TestPeerCacheLoaderPanic(t *testing.T) {
+	down := PeerGetterFunc(func(ctx context.Context, key string) ([]byte, error) {
+		return nil, errors.New("peer down")
+	})
+	var panics atomic.Int32
+	cache := NewPeerCache(PeerCacheOption[string]{
+		Peers: NewPeers("a", 0),
+		Loader: func(ctx context.Context, key string) (string, error) {
+			if panics.Add(1) == 1 {
+				panic("boom")
+			}
+			return "local:" + key, nil
+		},
+	})
+	defer cache.Close()
+	cache.opts.Peers.Set(map[string]PeerGetter{"b": down})
+	key := ""
+	for i := 0; key == ""; i++ {
+		if _, remote := cache.opts.Peers.Pick(fmt.Sprint(i)); remote {
+			key = fmt.Sprint(i)
+		}
+	}
+
+	// 退回本节点加载时 panic，之后对同一个键的请求不会一直等待
+	func() {
+		defer func() { recover() }()
+		cache.Get(context.Background(), key)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if v, err := cache.Get(ctx, key); err != nil || v != "local:"+key {
+		t.Errorf("Expected later Get to load again, got %q, err: %v", v, err)
+	}
+}
+
+func TestHTTPPeer(t *testing.T) {
+	owner := NewPeerCache(PeerCacheOption[int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			if key == "missing" {
+				return 0, ErrNotFound
+			}
+			return len(key), nil
+		},
+	})
+	defer owner.Close()
+	server := httptest.NewServer(owner)
+	defer server.Close()
+
+	peer := HTTPPeer{URL: server.URL}
+	data, err := peer.Get(context.Background(), "a b&c")
+	if err != nil || string(data) != "5" {
+		t.Errorf("Expected encoded 5, got %q, err: %v", data, err)
+	}
+	if _, err := peer.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
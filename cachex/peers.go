@@ -0,0 +1,138 @@
+package cachex
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// PeerGetter 向远端节点请求键对应的值，远端节点负责加载并返回编码后的值
+// 键在远端不存在时应返回 ErrNotFound
+type PeerGetter interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// PeerGetterFunc 以函数实现 PeerGetter
+type PeerGetterFunc func(ctx context.Context, key string) ([]byte, error)
+
+func (f PeerGetterFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
+
+// HTTPPeer 通过 HTTP 访问远端节点上的 PeerCache
+type HTTPPeer struct {
+	// URL 远端 PeerCache 挂载的地址
+	URL string
+	// Client 发送请求使用的客户端，为空时使用 http.DefaultClient
+	Client *http.Client
+}
+
+func (p HTTPPeer) Get(ctx context.Context, key string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL+"?key="+url.QueryEscape(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("cachex: peer %s returned %s", p.URL, resp.Status)
+	}
+}
+
+// Peers 按一致性哈希把键分配给集群中的节点，节点增减时只有少量键改变归属
+// 哈希不依赖进程内的随机种子，同样的节点列表在所有节点上得到相同的分配结果
+type Peers struct {
+	self     string
+	replicas int
+
+	mu      sync.RWMutex
+	ring    []uint32          // 所有虚拟节点的哈希，升序排列
+	owners  map[uint32]string // 虚拟节点的哈希到节点名称
+	getters map[string]PeerGetter
+}
+
+// NewPeers 创建节点集合，self 为本节点的名称，replicas 为每个节点的虚拟节点数，小于等于0时为50
+func NewPeers(self string, replicas int) *Peers {
+	if replicas <= 0 {
+		replicas = 50
+	}
+	p := &Peers{self: self, replicas: replicas}
+	p.Set(nil)
+	return p
+}
+
+// Set 替换集群中的节点，getters 为其他节点的名称到访问方式，本节点总是包含在内，无需出现在 getters 中
+func (p *Peers) Set(getters map[string]PeerGetter) {
+	// 复制一份，调用方之后修改 getters 不影响 Pick
+	copied := make(map[string]PeerGetter, len(getters))
+	for name, getter := range getters {
+		copied[name] = getter
+	}
+	names := make([]string, 0, len(getters)+1)
+	names = append(names, p.self)
+	for name := range getters {
+		if name != p.self {
+			names = append(names, name)
+		}
+	}
+	ring := make([]uint32, 0, len(names)*p.replicas)
+	owners := make(map[uint32]string, len(names)*p.replicas)
+	for _, name := range names {
+		for i := 0; i < p.replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + name))
+			ring = append(ring, h)
+			owners[h] = name
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i] < ring[j] })
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ring, p.owners, p.getters = ring, owners, copied
+}
+
+// Owner 返回负责该键的节点名称
+func (p *Peers) Owner(key string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.owner(key)
+}
+
+func (p *Peers) owner(key string) string {
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(p.ring), func(i int) bool { return p.ring[i] >= h })
+	if i == len(p.ring) {
+		i = 0
+	}
+	return p.owners[p.ring[i]]
+}
+
+// Pick 返回负责该键的远端节点，键由本节点负责时返回 false
+func (p *Peers) Pick(key string) (PeerGetter, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	owner := p.owner(key)
+	if owner == p.self {
+		return nil, false
+	}
+	getter, ok := p.getters[owner]
+	return getter, ok && getter != nil
+}
@@ -0,0 +1,87 @@
+package cachex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestPeersConsistentHashing(t *testing.T) {
+	nodes := map[string]PeerGetter{"b": nil, "c": nil}
+	p1 := NewPeers("a", 0)
+	p1.Set(nodes)
+	p2 := NewPeers("b", 0)
+	p2.Set(map[string]PeerGetter{"a": nil, "c": nil})
+
+	// 不同节点上计算出的归属一致，且键分散到所有节点
+	owners := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("key", i)
+		if p1.Owner(key) != p2.Owner(key) {
+			t.Fatalf("Expected the same owner for %q on every node", key)
+		}
+		owners[p1.Owner(key)]++
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if owners[name] < 100 {
+			t.Errorf("Expected node %q to own a fair share of keys, got %d", name, owners[name])
+		}
+	}
+
+	// 增加节点只改变部分键的归属
+	nodes["d"] = nil
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("key", i)
+		before[key] = p1.Owner(key)
+	}
+	p1.Set(nodes)
+	moved := 0
+	for key, owner := range before {
+		if now := p1.Owner(key); now != owner {
+			if now != "d" {
+				t.Fatalf("Expected %q to move only to the new node, moved to %q", key, now)
+			}
+			moved++
+		}
+	}
+	if moved == 0 || moved > 500 {
+		t.Errorf("Expected a minority of keys to move, got %d", moved)
+	}
+}
+
+func TestPeersPick(t *testing.T) {
+	remote := PeerGetterFunc(func(ctx context.Context, key string) ([]byte, error) { return nil, nil })
+	p := NewPeers("a", 0)
+	p.Set(map[string]PeerGetter{"b": remote})
+	var local, picked int
+	for i := 0; i < 100; i++ {
+		if _, ok := p.Pick(fmt.Sprint(i)); ok {
+			picked++
+		} else {
+			local++
+		}
+	}
+	if local == 0 || picked == 0 {
+		t.Errorf("Expected keys on both nodes, got %d local and %d remote", local, picked)
+	}
+}
+
+func TestPeersSetCopies(t *testing.T) {
+	remote := PeerGetterFunc(func(ctx context.Context, key string) ([]byte, error) { return nil, nil })
+	getters := map[string]PeerGetter{"b": remote}
+	p := NewPeers("a", 0)
+	p.Set(getters)
+
+	// 之后修改传入的 getters 不影响已设置的节点
+	delete(getters, "b")
+	picked := 0
+	for i := 0; i < 100; i++ {
+		if peer, ok := p.Pick(fmt.Sprint(i)); ok && peer != nil {
+			picked++
+		}
+	}
+	if picked == 0 {
+		t.Errorf("Expected Set to keep its own copy of getters")
+	}
+}
@@ -0,0 +1,40 @@
+package cachex
+
+// Pin 固定键，固定的条目不会过期，也不会因容量被淘汰，不计入 MaxEntries，成本仍计入 MaxCost
+// 固定期间替换值或修改过期时间仍保持固定，直到 Unpin 或 Del，缓存销毁时与其他条目一同释放
+// 键不存在或已失效时返回 false
+func (c *BaseCache[K, V]) Pin(key K) bool {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	item := s.getItem(key, c.now())
+	if item == nil || item.negative {
+		return false
+	}
+	if !item.pinned {
+		s.pin(item)
+	}
+	return true
+}
+
+// Unpin 取消固定，条目重新按原有的过期时间参与过期和容量淘汰，键不存在或未固定时返回 false
+func (c *BaseCache[K, V]) Unpin(key K) bool {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	item, ok := s.cache[key]
+	if !ok || !item.pinned {
+		return false
+	}
+	s.unpin(item)
+	return true
+}
+
+// Pinned 判断键是否被固定
+func (c *BaseCache[K, V]) Pinned(key K) bool {
+	s := c.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.cache[key]
+	return ok && item.pinned
+}
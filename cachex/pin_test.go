@@ -0,0 +1,59 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPin(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries:    2,
+		CheckInterval: time.Millisecond,
+	})
+	cache.SetExpire("config", 1, 5*time.Millisecond)
+	if !cache.Pin("config") || !cache.Pinned("config") {
+		t.Fatalf("Expected Pin to succeed")
+	}
+
+	// 固定的条目不会被容量淘汰，也不会过期
+	for i := 0; i < 10; i++ {
+		cache.Set(string(rune('a'+i)), i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if v, ok := cache.Get("config"); !ok || v != 1 {
+		t.Errorf("Expected pinned entry to survive, got %v, ok: %v", v, ok)
+	}
+	if n := cache.Len(); n != 3 {
+		t.Errorf("Expected pinned entry not to count toward MaxEntries, got %d entries", n)
+	}
+	cache.Set("config", 2)
+	if !cache.Pinned("config") {
+		t.Errorf("Expected replaced entry to stay pinned")
+	}
+
+	// 取消固定后按原有规则处理
+	cache.SetExpire("config", 3, 5*time.Millisecond)
+	if !cache.Unpin("config") || cache.Unpin("config") {
+		t.Errorf("Expected Unpin to succeed once")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get("config"); ok {
+		t.Errorf("Expected unpinned entry to expire")
+	}
+	if cache.Pin("missing") {
+		t.Errorf("Expected Pin on missing key to fail")
+	}
+
+	var destroyed []string
+	cache.opts.OnEvict = func(key string, value int, reason EvictReason) {
+		if reason == EvictDestroyed {
+			destroyed = append(destroyed, key)
+		}
+	}
+	cache.Set("x", 1)
+	cache.Pin("x")
+	cache.Close()
+	if len(destroyed) == 0 || !cache.Closed() {
+		t.Errorf("Expected pinned entries to be destroyed with the cache")
+	}
+}
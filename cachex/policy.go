@@ -0,0 +1,12 @@
+package cachex
+
+// EvictionPolicy 限制容量时的淘汰算法
+type EvictionPolicy int
+
+const (
+	// PolicyLRU 严格的 LRU，每次命中都在写锁内把条目移到访问顺序的队首
+	PolicyLRU EvictionPolicy = iota
+	// PolicyClock CLOCK 算法，命中时只设置条目的访问标记，读取只需要读锁
+	// 淘汰时从最早写入的条目开始检查，有标记的清除标记后保留，没有标记的淘汰，命中率接近 LRU
+	PolicyClock
+)
@@ -0,0 +1,58 @@
+package cachex
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestPolicyClock(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 3, EvictionPolicy: PolicyClock})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	// a 被访问过，获得第二次机会，淘汰最早写入且未被访问的 b
+	cache.Get("a")
+	cache.Set("d", 4)
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %q to remain", key)
+		}
+	}
+
+	// 全部被访问过时清除标记后按写入顺序淘汰
+	cache.Set("e", 5)
+	if n := cache.Len(); n != 3 {
+		t.Errorf("Expected 3 entries, got %d", n)
+	}
+	if _, ok := cache.Get("e"); !ok {
+		t.Errorf("Expected newest entry to remain")
+	}
+}
+
+func TestPolicyClockConcurrent(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 50, Shards: 4, EvictionPolicy: PolicyClock})
+	defer cache.Close()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprint((i * (g + 1)) % 200)
+				if _, ok := cache.Get(key); !ok {
+					cache.Set(key, i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := cache.Len(); n > 52 {
+		t.Errorf("Expected entries to stay within capacity, got %d", n)
+	}
+}
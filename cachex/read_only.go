@@ -0,0 +1,71 @@
+package cachex
+
+import "time"
+
+// ReadOnlyCache 缓存某一时刻的只读视图
+type ReadOnlyCache[K comparable, V any] interface {
+	// Get 获取快照时刻的值
+	Get(key K) (V, bool)
+	// Entry 获取快照时刻的条目及其元数据，TTL 相对于快照时刻计算
+	Entry(key K) (Entry[V], bool)
+	// Keys 返回快照中所有的键，顺序不固定
+	Keys() []K
+	// Range 遍历快照中的条目，fn 返回 false 时停止
+	Range(fn func(key K, value V) bool)
+	Len() int
+	// Time 快照的时刻
+	Time() time.Time
+}
+
+// frozenCache 快照时复制出的独立 map，之后不再修改，读取无需加锁
+type frozenCache[K comparable, V any] struct {
+	entries map[K]Entry[V]
+	at      time.Time
+}
+
+// Snapshot 返回缓存当前时刻的只读视图，之后对缓存的修改和条目的过期都不会影响视图
+// 复制期间同时持有所有分片的读锁，复制完成后视图的读取和遍历不再持有缓存的锁，适合对大缓存生成报表
+// 视图中的值与缓存共享，设置了 CloneOnGet 时为副本
+func (c *BaseCache[K, V]) Snapshot() ReadOnlyCache[K, V] {
+	now := c.now()
+	items := c.snapshotItems()
+	entries := make(map[K]Entry[V], len(items))
+	for i := range items {
+		entries[items[i].key] = newEntry(&items[i], now)
+	}
+	return &frozenCache[K, V]{entries: entries, at: now}
+}
+
+func (f *frozenCache[K, V]) Get(key K) (V, bool) {
+	e, ok := f.entries[key]
+	return e.Value, ok
+}
+
+func (f *frozenCache[K, V]) Entry(key K) (Entry[V], bool) {
+	e, ok := f.entries[key]
+	return e, ok
+}
+
+func (f *frozenCache[K, V]) Keys() []K {
+	keys := make([]K, 0, len(f.entries))
+	for key := range f.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (f *frozenCache[K, V]) Range(fn func(key K, value V) bool) {
+	for key, e := range f.entries {
+		if !fn(key, e.Value) {
+			return
+		}
+	}
+}
+
+func (f *frozenCache[K, V]) Len() int {
+	return len(f.entries)
+}
+
+func (f *frozenCache[K, V]) Time() time.Time {
+	return f.at
+}
@@ -0,0 +1,42 @@
+package cachex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadOnlySnapshot(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, []int]{CheckInterval: time.Millisecond})
+	defer cache.Close()
+	cache.Set("a", []int{1})
+	cache.SetExpire("b", []int{2}, 5*time.Millisecond)
+
+	view := cache.Snapshot()
+	cache.Set("c", []int{3})
+	cache.Del("a")
+	time.Sleep(20 * time.Millisecond)
+
+	if view.Len() != 2 || len(view.Keys()) != 2 {
+		t.Fatalf("Expected 2 entries in snapshot, got %d", view.Len())
+	}
+	if v, ok := view.Get("a"); !ok || v[0] != 1 {
+		t.Errorf("Expected deleted key to remain in snapshot, got %v, ok: %v", v, ok)
+	}
+	if e, ok := view.Entry("b"); !ok || e.TTL <= 0 || e.TTL > 5*time.Millisecond {
+		t.Errorf("Expected expired key to remain with TTL at snapshot time, got %+v", e)
+	}
+	if _, ok := view.Get("c"); ok {
+		t.Errorf("Expected later writes to be invisible")
+	}
+	n := 0
+	view.Range(func(key string, value []int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Expected Range to stop early, visited %d", n)
+	}
+	if view.Time().IsZero() {
+		t.Errorf("Expected snapshot time")
+	}
+}
@@ -0,0 +1,61 @@
+package redisstore
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/redis/go-redis/v9"
+)
+
+// Invalidator 基于 Redis 发布订阅的 cachex.Invalidator 实现，所有副本订阅同一个频道
+type Invalidator struct {
+	client  redis.UniversalClient
+	channel string
+	// OnError 收到无法解析的消息时的回调
+	OnError func(err error)
+}
+
+var _ cachex.Invalidator = (*Invalidator)(nil)
+
+// NewInvalidator 创建使用 channel 频道传递失效通知的 Invalidator
+func NewInvalidator(client redis.UniversalClient, channel string) *Invalidator {
+	return &Invalidator{client: client, channel: channel}
+}
+
+func (i *Invalidator) Publish(ctx context.Context, msg cachex.Invalidation) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return i.client.Publish(ctx, i.channel, data).Err()
+}
+
+// Subscribe 订阅频道并在后台协程中处理通知，返回前确认订阅已经生效
+func (i *Invalidator) Subscribe(fn func(msg cachex.Invalidation)) (func(), error) {
+	ctx := context.Background()
+	sub := i.client.Subscribe(ctx, i.channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	ch := sub.Channel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range ch {
+			var msg cachex.Invalidation
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				if i.OnError != nil {
+					i.OnError(err)
+				}
+				continue
+			}
+			fn(msg)
+		}
+	}()
+	return func() {
+		sub.Close()
+		<-done
+	}, nil
+}
@@ -0,0 +1,40 @@
+package redisstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/llyb120/gotool/cachex"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestInvalidator(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	// 两个副本各自持有本地缓存，通过 Redis 频道同步失效
+	a := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{Invalidator: NewInvalidator(client, "cache:invalidate")})
+	b := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{Invalidator: NewInvalidator(client, "cache:invalidate")})
+	defer a.Close()
+	defer b.Close()
+
+	a.Set("k", 1)
+	// 等待 a 的通知送达 b，避免其晚于 b 的写入到达
+	time.Sleep(50 * time.Millisecond)
+	b.Set("k", 2)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := a.Get("k"); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected write on b to invalidate a")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if v, ok := b.Get("k"); !ok || v != 2 {
+		t.Errorf("Expected b to ignore its own invalidation, got %v, ok: %v", v, ok)
+	}
+}
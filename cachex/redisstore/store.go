@@ -0,0 +1,59 @@
+package redisstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store 基于 Redis 的 cachex.Store 实现
+type Store struct {
+	client redis.UniversalClient
+}
+
+var _ cachex.Store = (*Store)(nil)
+
+// New 使用已有的 Redis 客户端创建存储，单机、哨兵和集群客户端均可
+func New(client redis.UniversalClient) *Store {
+	return &Store{client: client}
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *Store) Del(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+func (s *Store) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	ttl, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	// -2 表示键不存在，-1 表示永不过期
+	switch ttl {
+	case -2, -2 * time.Millisecond:
+		return 0, false, nil
+	case -1, -1 * time.Millisecond:
+		return -1, true, nil
+	}
+	return ttl, true, nil
+}
@@ -0,0 +1,73 @@
+package redisstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/llyb120/gotool/cachex"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	store := New(client)
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "a"); ok || err != nil {
+		t.Errorf("Expected miss without error, got ok: %v, err: %v", ok, err)
+	}
+	if _, ok, err := store.TTL(ctx, "a"); ok || err != nil {
+		t.Errorf("Expected missing ttl, got ok: %v, err: %v", ok, err)
+	}
+
+	if err := store.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if v, ok, err := store.Get(ctx, "a"); !ok || err != nil || string(v) != "1" {
+		t.Errorf("Expected 1, got %s, ok: %v, err: %v", v, ok, err)
+	}
+	if ttl, ok, err := store.TTL(ctx, "a"); !ok || err != nil || ttl >= 0 {
+		t.Errorf("Expected negative ttl for key without expire, got %v, ok: %v, err: %v", ttl, ok, err)
+	}
+
+	store.Set(ctx, "b", []byte("2"), time.Minute)
+	if ttl, ok, err := store.TTL(ctx, "b"); !ok || err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected ttl within a minute, got %v, ok: %v, err: %v", ttl, ok, err)
+	}
+	mr.FastForward(2 * time.Minute)
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Errorf("Expected 'b' to be expired")
+	}
+
+	if err := store.Del(ctx, "a"); err != nil {
+		t.Errorf("Del failed: %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "a"); ok {
+		t.Errorf("Expected 'a' to be deleted")
+	}
+}
+
+func TestStoreCacheOverRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	// 两个缓存实例共享同一个 Redis，模拟多个进程
+	opts := cachex.StoreCacheOption{Prefix: "users:", DefaultKeyExpire: time.Minute}
+	c1 := cachex.NewStoreCache[string](New(client), opts)
+	c2 := cachex.NewStoreCache[string](New(client), opts)
+	defer c1.Close()
+	defer c2.Close()
+
+	c1.Set("1", "alice")
+	if v, ok := c2.Get("1"); !ok || v != "alice" {
+		t.Errorf("Expected shared value, got %v, ok: %v", v, ok)
+	}
+	if !mr.Exists("users:1") {
+		t.Errorf("Expected prefixed key in redis")
+	}
+}
@@ -0,0 +1,41 @@
+package cachex
+
+import "context"
+
+type requestCacheKey struct{}
+
+// WithRequestCache 返回带有请求级缓存的 ctx，同一个请求的调用链中重复的查询只执行一次
+// ctx 结束时缓存自动销毁，ctx 中已有请求级缓存时直接返回 ctx
+func WithRequestCache(ctx context.Context) context.Context {
+	if FromContext(ctx) != nil {
+		return ctx
+	}
+	cache := NewBaseCacheCtx(ctx, OnceCacheOption[string, any]{})
+	return context.WithValue(ctx, requestCacheKey{}, cache)
+}
+
+// FromContext 返回 ctx 中的请求级缓存，没有时返回 nil
+func FromContext(ctx context.Context) *BaseCache[string, any] {
+	cache, _ := ctx.Value(requestCacheKey{}).(*BaseCache[string, any])
+	return cache
+}
+
+// RequestLoad 在请求级缓存中查找 key，不存在时调用 fn 加载，并发的相同查询只执行一次
+// ctx 中没有请求级缓存时直接调用 fn，缓存中的值类型与 T 不符时同样直接调用 fn
+func RequestLoad[T any](ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	cache := FromContext(ctx)
+	if cache == nil {
+		return fn(ctx)
+	}
+	value, err := cache.GetOrSetFuncCtx(ctx, key, func(ctx context.Context) (any, error) {
+		return fn(ctx)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,55 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if FromContext(ctx) != nil {
+		t.Fatalf("Expected no request cache")
+	}
+	ctx = WithRequestCache(ctx)
+	cache := FromContext(ctx)
+	if cache == nil || FromContext(WithRequestCache(ctx)) != cache {
+		t.Fatalf("Expected the same request cache to be reused")
+	}
+
+	calls := 0
+	load := func(ctx context.Context) (string, error) {
+		calls++
+		return "alice", nil
+	}
+	for i := 0; i < 3; i++ {
+		if v, err := RequestLoad(ctx, "user:1", load); err != nil || v != "alice" {
+			t.Errorf("Expected alice, got %v, err: %v", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected repeated lookups to be deduplicated, got %d", calls)
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for !cache.Closed() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected request cache to be destroyed with its context")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRequestLoadWithoutCache(t *testing.T) {
+	calls := 0
+	for i := 0; i < 2; i++ {
+		RequestLoad(context.Background(), "k", func(ctx context.Context) (int, error) {
+			calls++
+			return 1, nil
+		})
+	}
+	if calls != 2 {
+		t.Errorf("Expected fn to be called directly without request cache, got %d", calls)
+	}
+}
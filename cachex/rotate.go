@@ -0,0 +1,56 @@
+package cachex
+
+// Rotate 原子地换上一代空的缓存并在后台销毁旧的一代
+// 同时持有所有分片的锁完成替换，之后的读取要么看到全部旧条目要么一个也看不到，替换完成后立即可以继续读写
+// 旧条目在后台以 EvictDestroyed 通知 OnEvict，然后把其中未失效的条目交给 OnDestroy，Destroy 只在缓存本身销毁时调用
+// 适合定期整批刷新数据的场景，替换时正在进行的加载会把结果写入新的一代
+func (c *BaseCache[K, V]) Rotate() {
+	for _, s := range c.shards {
+		s.lock()
+	}
+	if c.closed.Load() {
+		for _, s := range c.shards {
+			s.unlock()
+		}
+		return
+	}
+	old := make([]map[K]*cacheItemWrapper[K, V], len(c.shards))
+	for i, s := range c.shards {
+		old[i] = s.cache
+		s.cache = make(map[K]*cacheItemWrapper[K, V])
+		s.lru.Init()
+		s.expiry = nil
+		s.cost = 0
+		s.tags = nil
+		if s.reads != nil {
+			for key := range old[i] {
+				s.reads.Delete(key)
+			}
+		}
+	}
+	now := c.now()
+	for _, s := range c.shards {
+		s.unlock()
+	}
+	c.publish(Invalidation{All: true})
+
+	go func() {
+		var remaining map[K]V
+		if c.opts.OnDestroy != nil {
+			remaining = make(map[K]V)
+		}
+		for _, items := range old {
+			for key, item := range items {
+				if c.opts.OnEvict != nil {
+					c.opts.OnEvict(key, item.value, EvictDestroyed)
+				}
+				if remaining != nil && !item.negative && !item.expired(now) {
+					remaining[key] = item.value
+				}
+			}
+		}
+		if c.opts.OnDestroy != nil {
+			c.opts.OnDestroy(remaining)
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package cachex
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRotate(t *testing.T) {
+	destroyed := make(chan map[string]int, 1)
+	var evicted atomic.Int32
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		MaxEntries: 10,
+		OnDestroy:  func(remaining map[string]int) { destroyed <- remaining },
+		OnEvict: func(key string, value int, reason EvictReason) {
+			if reason == EvictDestroyed {
+				evicted.Add(1)
+			}
+		},
+	})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	cache.Rotate()
+	if n := cache.Len(); n != 0 {
+		t.Errorf("Expected empty generation, got %d entries", n)
+	}
+	cache.Set("c", 3)
+	if v, ok := cache.Get("c"); !ok || v != 3 {
+		t.Errorf("Expected new generation to serve writes, got %v, ok: %v", v, ok)
+	}
+
+	select {
+	case remaining := <-destroyed:
+		if len(remaining) != 2 || remaining["a"] != 1 || remaining["b"] != 2 {
+			t.Errorf("Expected old generation entries, got %v", remaining)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected old generation to be destroyed")
+	}
+	if n := evicted.Load(); n != 2 {
+		t.Errorf("Expected 2 destroyed evictions, got %d", n)
+	}
+
+	// 新一代的容量限制和访问顺序正常工作
+	for i := 0; i < 20; i++ {
+		cache.Set(string(rune('d'+i)), i)
+	}
+	if n := cache.Len(); n != 10 {
+		t.Errorf("Expected capacity to be enforced after rotation, got %d", n)
+	}
+}
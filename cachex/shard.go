@@ -0,0 +1,273 @@
+package cachex
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheShard 缓存分片，每个分片拥有独立的锁和数据
+type cacheShard[K comparable, V any] struct {
+	c          *BaseCache[K, V]
+	mu         sync.RWMutex
+	cache      map[K]*cacheItemWrapper[K, V]
+	lru        *list.List                // 按访问顺序排列的键，队首为最近访问，仅在限制容量时维护
+	expiry     expiryHeap[K, V]          // 按过期时间排列的条目，清理时只需处理堆顶已到期的部分
+	inflight   map[K]*flightCall[V]      // 正在加载中的键，保证同一个键只有一个加载函数在执行
+	cost       int64                     // 当前所有条目的总成本，仅在 MaxCost > 0 时维护
+	evicted    []evictedEntry[K, V]      // 等待通知 OnEvict 的条目，释放锁后统一回调
+	keyLocks   map[K]*keyMutex           // 调用方持有的键锁，没有持有者时删除
+	invalid    []string                  // 等待发布失效通知的键，释放锁后统一发布
+	failures   map[K]*loadFailure        // 加载失败且处于退避期的键，仅在 ErrorBackoff 启用时维护
+	waiters    map[K]*keyWaiter          // GetOrWait 等待写入的键，写入时关闭通道唤醒所有等待者
+	reads      *sync.Map                 // 条目的只读副本，仅在使用 BackendSyncMap 时维护
+	tags       map[string]map[K]struct{} // 标签到带有该标签的键，仅在使用 SetWithTags 后维护
+	stale      map[K]staleValue[V]       // 已过期条目的旧值，仅在 CircuitBreaker 启用时维护
+	maxEntries int
+	maxCost    int64
+}
+
+func newCacheShard[K comparable, V any](c *BaseCache[K, V], maxEntries int, maxCost int64) *cacheShard[K, V] {
+	return &cacheShard[K, V]{
+		c:          c,
+		cache:      make(map[K]*cacheItemWrapper[K, V]),
+		lru:        list.New(),
+		inflight:   make(map[K]*flightCall[V]),
+		failures:   make(map[K]*loadFailure),
+		keyLocks:   make(map[K]*keyMutex),
+		reads:      newReads(c),
+		waiters:    make(map[K]*keyWaiter),
+		maxEntries: maxEntries,
+		maxCost:    maxCost,
+	}
+}
+
+func (s *cacheShard[K, V]) lock() {
+	s.mu.Lock()
+}
+
+// unlock 释放写锁，并通知持有锁期间被移除的条目
+func (s *cacheShard[K, V]) unlock() {
+	s.c.notify(s.release())
+}
+
+// release 释放写锁，返回持有锁期间等待通知的条目和键，由调用方在释放所有锁之后交给 notify
+func (s *cacheShard[K, V]) release() ([]evictedEntry[K, V], []string) {
+	evicted, invalid := s.evicted, s.invalid
+	s.evicted, s.invalid = nil, nil
+	s.mu.Unlock()
+	return evicted, invalid
+}
+
+// 以下方法均需在持有写锁的情况下调用
+
+// getItem 获取未失效的条目并更新访问顺序，失效的条目会被删除，不存在时返回 nil
+func (s *cacheShard[K, V]) getItem(key K, now time.Time) *cacheItemWrapper[K, V] {
+	item, ok := s.cache[key]
+	if !ok {
+		return nil
+	}
+	if item.expired(now) {
+		s.removeItem(key, item, EvictExpired)
+		return nil
+	}
+	if item.elem != nil {
+		if s.c.opts.EvictionPolicy == PolicyClock {
+			item.access.ref.Store(true)
+		} else {
+			s.lru.MoveToFront(item.elem)
+		}
+	}
+	return item
+}
+
+// setItem 写入或替换条目，超出容量时淘汰最久未访问的键
+// 单个条目的成本超过 MaxCost 时该条目本身也会被淘汰
+// 替换已固定的条目时新条目保持固定
+func (s *cacheShard[K, V]) setItem(key K, item *cacheItemWrapper[K, V]) {
+	if old, ok := s.cache[key]; ok {
+		item.pinned = old.pinned
+		s.detachItem(key, old)
+	}
+	item.version = s.c.version.Add(1)
+	s.cache[key] = item
+	delete(s.stale, key)
+	s.publish(item)
+	s.tag(key, item)
+	s.c.emit(EventSet, key)
+	if w, ok := s.waiters[key]; ok && !item.negative {
+		close(w.ch)
+		delete(s.waiters, key)
+	}
+	if item.canExpire && !item.pinned {
+		heap.Push(&s.expiry, item)
+		s.c.nudgeSweep(item.deadline)
+	}
+	if !s.c.bounded() {
+		return
+	}
+	if !item.pinned {
+		item.elem = s.lru.PushFront(key)
+	}
+	if s.maxCost > 0 {
+		item.cost = s.c.weigh(key, item.value)
+		s.cost += item.cost
+	}
+	s.evictOverflow()
+}
+
+// evictOverflow 按最久未访问的顺序淘汰条目，直到不再超出容量
+// 使用 PolicyClock 时链表按写入顺序排列，队尾被访问过的条目清除标记后移到队首，获得第二次机会
+// 刚写入的条目位于队首，其余条目都被移到它前面之后才轮到它，此时同样跳过一次，与 CLOCK 指针越过新条目一致
+func (s *cacheShard[K, V]) evictOverflow() {
+	clock := s.c.opts.EvictionPolicy == PolicyClock
+	newest := s.lru.Front()
+	for s.overflow() {
+		oldest := s.lru.Back()
+		oldestKey := oldest.Value.(K)
+		item := s.cache[oldestKey]
+		if clock && (item.access.ref.Swap(false) || oldest == newest) {
+			if oldest == newest {
+				newest = nil
+			}
+			s.lru.MoveToFront(oldest)
+			continue
+		}
+		s.removeItem(oldestKey, item, EvictCapacity)
+	}
+}
+
+// invalidate 记录本地修改过的键，释放锁后通知其他副本
+func (s *cacheShard[K, V]) invalidate(key K) {
+	if s.c.opts.Invalidator != nil {
+		s.invalid = append(s.invalid, s.c.keyString(key))
+	}
+}
+
+// removeItem 从缓存中删除条目，并在释放锁后通知 OnEvict
+func (s *cacheShard[K, V]) removeItem(key K, item *cacheItemWrapper[K, V], reason EvictReason) {
+	s.detachItem(key, item)
+	switch reason {
+	case EvictExpired:
+		s.c.stats.evictions.Add(1)
+		s.c.emit(EventExpired, key)
+		s.keepStale(key, item)
+	case EvictCapacity:
+		s.c.stats.evictions.Add(1)
+		s.c.stats.capacity.Add(1)
+		s.c.emit(EventEvicted, key)
+	case EvictDeleted:
+		s.c.emit(EventDeleted, key)
+		delete(s.stale, key)
+	}
+	if s.c.opts.OnEvict != nil {
+		s.evicted = append(s.evicted, evictedEntry[K, V]{key: key, value: item.value, reason: reason})
+	}
+}
+
+// detachItem 从缓存中删除条目，不触发回调
+func (s *cacheShard[K, V]) detachItem(key K, item *cacheItemWrapper[K, V]) {
+	delete(s.cache, key)
+	if s.reads != nil {
+		s.reads.Delete(key)
+	}
+	s.untag(key, item)
+	if item.elem != nil {
+		s.lru.Remove(item.elem)
+		item.elem = nil
+	}
+	if item.heapIndex >= 0 {
+		heap.Remove(&s.expiry, item.heapIndex)
+	}
+	s.cost -= item.cost
+}
+
+// setExpire 修改条目的过期时间，并调整其在过期堆中的位置
+func (s *cacheShard[K, V]) setExpire(item *cacheItemWrapper[K, V], expire, deadline time.Time, canExpire bool) {
+	item.expire, item.deadline, item.canExpire = expire, deadline, canExpire
+	s.publish(item)
+	if item.pinned {
+		return
+	}
+	switch {
+	case !canExpire && item.heapIndex >= 0:
+		heap.Remove(&s.expiry, item.heapIndex)
+	case canExpire && item.heapIndex >= 0:
+		heap.Fix(&s.expiry, item.heapIndex)
+	case canExpire:
+		heap.Push(&s.expiry, item)
+	}
+}
+
+// pin 固定条目，将其移出过期堆和访问顺序链表
+func (s *cacheShard[K, V]) pin(item *cacheItemWrapper[K, V]) {
+	item.pinned = true
+	s.publish(item)
+	if item.heapIndex >= 0 {
+		heap.Remove(&s.expiry, item.heapIndex)
+	}
+	if item.elem != nil {
+		s.lru.Remove(item.elem)
+		item.elem = nil
+	}
+}
+
+// unpin 取消固定，条目重新参与过期和容量淘汰
+func (s *cacheShard[K, V]) unpin(item *cacheItemWrapper[K, V]) {
+	item.pinned = false
+	s.publish(item)
+	if item.canExpire {
+		heap.Push(&s.expiry, item)
+		s.c.nudgeSweep(item.deadline)
+	}
+	if s.c.bounded() {
+		item.elem = s.lru.PushFront(item.key)
+		s.evictOverflow()
+	}
+}
+
+// overflow 是否超出了条目数或成本上限
+func (s *cacheShard[K, V]) overflow() bool {
+	if s.lru.Len() == 0 {
+		return false
+	}
+	if s.maxEntries > 0 && s.lru.Len() > s.maxEntries {
+		return true
+	}
+	return s.maxCost > 0 && s.cost > s.maxCost
+}
+
+// delExpired 删除已过期的条目
+// 需要重新检查，避免误删在此期间被重新写入的值
+func (s *cacheShard[K, V]) delExpired(key K, now time.Time) {
+	if item, ok := s.cache[key]; ok && item.expired(now) {
+		s.removeItem(key, item, EvictExpired)
+	}
+}
+
+// sweep 删除所有已过期的条目，只访问堆顶已到期的条目
+func (s *cacheShard[K, V]) sweep(now time.Time) {
+	if len(s.failures) > 0 {
+		s.sweepFailures(now)
+	}
+	if len(s.stale) > 0 {
+		s.sweepStale(now)
+	}
+	for len(s.expiry) > 0 && s.expiry[0].expired(now) {
+		item := s.expiry[0]
+		s.removeItem(item.key, item, EvictExpired)
+	}
+}
+
+// clear 移除所有条目并释放内存，每个条目以 reason 通知 OnEvict
+func (s *cacheShard[K, V]) clear(reason EvictReason) {
+	for key, item := range s.cache {
+		s.removeItem(key, item, reason)
+	}
+	s.cache = make(map[K]*cacheItemWrapper[K, V])
+	s.stale = nil
+	s.lru.Init()
+	s.expiry = nil
+	s.cost = 0
+}
@@ -0,0 +1,84 @@
+package cachex
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Sizer 可以报告自身内存占用的值，实现后 EstimateSize 直接使用其返回的字节数
+type Sizer interface {
+	Size() int64
+}
+
+// entryOverhead 每个条目在缓存内部结构上的大致开销，包括条目本身、map 槽位和链表节点
+const entryOverhead = int64(unsafe.Sizeof(cacheItemWrapper[struct{}, struct{}]{})) + 64
+
+// EstimateSize 估算值占用的内存字节数，实现了 Sizer 的值使用其自身的结果
+// 其余值通过反射递归计算，指针指向的同一块内存只计算一次，结果是近似值
+func EstimateSize(v any) int64 {
+	if v == nil {
+		return 0
+	}
+	if s, ok := v.(Sizer); ok {
+		return s.Size()
+	}
+	rv := reflect.ValueOf(v)
+	return int64(rv.Type().Size()) + indirectSize(rv, make(map[uintptr]bool))
+}
+
+// indirectSize 计算值通过指针、切片、字符串等间接引用的内存，不包括值本身
+func indirectSize(v reflect.Value, seen map[uintptr]bool) int64 {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + indirectSize(elem, seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + indirectSize(elem, seen)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		// 按每个槽位额外1字节的控制信息粗略估算桶的开销
+		slot := int64(v.Type().Key().Size()+v.Type().Elem().Size()) + 1
+		size := int64(v.Len()) * slot
+		iter := v.MapRange()
+		for iter.Next() {
+			size += indirectSize(iter.Key(), seen) + indirectSize(iter.Value(), seen)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += indirectSize(v.Field(i), seen)
+		}
+		return size
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package cachex
+
+import (
+	"strings"
+	"testing"
+)
+
+type sizedValue struct{}
+
+func (sizedValue) Size() int64 {
+	return 1000
+}
+
+func TestEstimateSize(t *testing.T) {
+	if n := EstimateSize(sizedValue{}); n != 1000 {
+		t.Errorf("Expected Sizer to be used, got %d", n)
+	}
+	if n := EstimateSize(strings.Repeat("a", 100)); n != 116 {
+		t.Errorf("Expected string header plus data, got %d", n)
+	}
+	if n := EstimateSize(make([]int64, 10)); n != 24+80 {
+		t.Errorf("Expected slice header plus backing array, got %d", n)
+	}
+
+	type node struct {
+		Name string
+		Next *node
+	}
+	a := &node{Name: "a"}
+	a.Next = a
+	if n := EstimateSize(a); n <= 0 || n > 100 {
+		t.Errorf("Expected cyclic pointers to be counted once, got %d", n)
+	}
+
+	small := EstimateSize(map[string][]byte{"a": make([]byte, 10)})
+	large := EstimateSize(map[string][]byte{"a": make([]byte, 1000)})
+	if large-small != 990 {
+		t.Errorf("Expected map values to be counted, got %d and %d", small, large)
+	}
+}
+
+func TestMaxMemory(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[int, []byte]{MaxMemory: 10 << 10})
+	defer cache.Close()
+
+	for i := 0; i < 100; i++ {
+		cache.Set(i, make([]byte, 1<<10))
+	}
+	if n := cache.Len(); n == 0 || n >= 10 {
+		t.Errorf("Expected roughly 9 entries of 1KB to fit in 10KB, got %d", n)
+	}
+	if s := cache.shards[0]; s.cost > 10<<10 {
+		t.Errorf("Expected estimated memory within budget, got %d", s.cost)
+	}
+	if _, ok := cache.Get(99); !ok {
+		t.Errorf("Expected newest entry to be kept")
+	}
+}
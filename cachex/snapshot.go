@@ -0,0 +1,96 @@
+package cachex
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"time"
+)
+
+// snapshotMagic 快照开头的标识，WarmFrom 据此区分快照和 JSON Lines
+const snapshotMagic = "cachex-snapshot/1\n"
+
+// snapshotEntry 快照中的单个条目，Expire 与 Deadline 为零值时表示永不过期
+type snapshotEntry[K comparable, V any] struct {
+	Key      K
+	Value    V
+	Created  time.Time
+	Expire   time.Time
+	Deadline time.Time
+}
+
+// Save 将缓存中未过期的条目写入 w，以 snapshotMagic 开头，之后为 gob 编码的条目，SetNegative 的记录不会写入，键和值的类型需要能被 gob 编码
+// 写入期间逐个分片加读锁，得到的快照在分片之间不保证是同一时刻的
+func (c *BaseCache[K, V]) Save(w io.Writer) error {
+	now := c.now()
+	var entries []snapshotEntry[K, V]
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for key, item := range s.cache {
+			if item.expired(now) || item.negative {
+				continue
+			}
+			entry := snapshotEntry[K, V]{Key: key, Value: item.value, Created: item.created}
+			if item.canExpire {
+				entry.Expire = item.expire
+				entry.Deadline = item.deadline
+			}
+			entries = append(entries, entry)
+		}
+		s.mu.RUnlock()
+	}
+	if _, err := io.WriteString(w, snapshotMagic); err != nil {
+		return err
+	}
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+// readSnapshot 读取 Save 写出的快照，不以 snapshotMagic 开头时返回错误
+func readSnapshot[K comparable, V any](r io.Reader) ([]snapshotEntry[K, V], error) {
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != snapshotMagic {
+		return nil, errors.New("cachex: not a cache snapshot")
+	}
+	var entries []snapshotEntry[K, V]
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// NewBaseCacheFromSnapshot 创建缓存并载入 Save 写出的快照，快照中已过期的条目会被跳过
+// 条目保留原有的过期时间，容量限制按 opts 重新生效
+func NewBaseCacheFromSnapshot[K comparable, V any](r io.Reader, opts OnceCacheOption[K, V]) (*BaseCache[K, V], error) {
+	entries, err := readSnapshot[K, V](r)
+	if err != nil {
+		return nil, err
+	}
+	c := NewBaseCache(opts)
+	c.restore(entries)
+	return c, nil
+}
+
+// restore 载入快照中的条目，跳过已过期的条目
+func (c *BaseCache[K, V]) restore(entries []snapshotEntry[K, V]) {
+	now := c.now()
+	for _, entry := range entries {
+		canExpire := !entry.Deadline.IsZero()
+		if canExpire && !now.Before(entry.Deadline) {
+			continue
+		}
+		item := &cacheItemWrapper[K, V]{
+			key:       entry.Key,
+			value:     entry.Value,
+			created:   entry.Created,
+			expire:    entry.Expire,
+			deadline:  entry.Deadline,
+			canExpire: canExpire,
+			heapIndex: -1,
+			access:    new(itemAccess),
+		}
+		s := c.shard(entry.Key)
+		s.lock()
+		s.setItem(entry.Key, item)
+		s.unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package cachex
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, user]{})
+	defer cache.Close()
+	cache.Set("alice", user{Name: "alice", Age: 20})
+	cache.SetExpire("bob", user{Name: "bob", Age: 30}, time.Minute)
+	cache.SetExpire("carol", user{Name: "carol", Age: 40}, 10*time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	restored, err := NewBaseCacheFromSnapshot(&buf, OnceCacheOption[string, user]{})
+	if err != nil {
+		t.Fatalf("NewBaseCacheFromSnapshot failed: %v", err)
+	}
+	defer restored.Close()
+
+	if v, ok := restored.Get("alice"); !ok || v.Age != 20 {
+		t.Errorf("Expected alice to be restored, got %v, ok: %v", v, ok)
+	}
+	if v, ok := restored.Get("bob"); !ok || v.Age != 30 {
+		t.Errorf("Expected bob to be restored, got %v, ok: %v", v, ok)
+	}
+	if _, ok := restored.Get("carol"); ok {
+		t.Errorf("Expected expired carol to be skipped")
+	}
+	// 恢复的条目保留原有的过期时间
+	item := restored.shard("bob").cache["bob"]
+	if !item.canExpire || time.Until(item.deadline) > time.Minute {
+		t.Errorf("Expected bob to keep its deadline, got %v", item.deadline)
+	}
+}
+
+func TestSnapshotInvalid(t *testing.T) {
+	if _, err := NewBaseCacheFromSnapshot(bytes.NewBufferString("bad"), OnceCacheOption[string, int]{}); err == nil {
+		t.Errorf("Expected error for invalid snapshot")
+	}
+}
@@ -0,0 +1,55 @@
+package cachex
+
+import "time"
+
+// SetExpires 写入键值对并分别指定软、硬两个有效期
+// 超过 soft 之后条目变旧，Get 仍会返回旧值，设置了 Loader 时触发后台刷新，GetFresh 视为未命中；
+// 超过 hard 之后条目彻底失效，后台清理只删除硬过期的条目，hard 小于 soft 时按 soft 处理，soft 小于等于0时忽略 hard，条目永不过期
+func (c *BaseCache[K, V]) SetExpires(key K, value V, soft, hard time.Duration) {
+	if soft <= 0 {
+		// 永不过期，hard 同样不再生效
+		hard = 0
+	} else if hard < soft {
+		hard = soft
+	}
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return
+	}
+	if c.writer != nil && !c.writer.set(key, value, hard) {
+		return
+	}
+	item := newCacheItem(key, value, hard, c.now())
+	if item.canExpire {
+		item.expire = item.created.Add(soft)
+	}
+	s.setItem(key, item)
+	s.invalidate(key)
+}
+
+// GetFresh 只返回未过软有效期的值，变旧的条目视为未命中，设置了 Loader 时同样会触发后台刷新
+func (c *BaseCache[K, V]) GetFresh(key K) (V, bool) {
+	now := c.now()
+	value, stale, ok := c.getAllowStale(key, now)
+	ok = ok && !stale
+	c.recordAccess(key, ok)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return value, true
+}
+
+// GetAllowStale 返回未过硬有效期的值，stale 表示值已经过了软有效期
+func (c *BaseCache[K, V]) GetAllowStale(key K) (value V, stale bool, ok bool) {
+	value, stale, ok = c.getAllowStale(key, c.now())
+	c.recordAccess(key, ok)
+	return value, stale, ok
+}
+
+func (c *BaseCache[K, V]) getAllowStale(key K, now time.Time) (V, bool, bool) {
+	item, ok := c.lookup(key, now)
+	return item.value, ok && item.stale(now), ok
+}
@@ -0,0 +1,82 @@
+package cachex
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetExpires(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{CheckInterval: time.Millisecond})
+	defer cache.Close()
+	cache.SetExpires("key", 1, 10*time.Millisecond, 60*time.Millisecond)
+
+	if v, ok := cache.GetFresh("key"); !ok || v != 1 {
+		t.Errorf("Expected fresh value, got %v, ok: %v", v, ok)
+	}
+	if _, stale, ok := cache.GetAllowStale("key"); !ok || stale {
+		t.Errorf("Expected fresh value, stale: %v, ok: %v", stale, ok)
+	}
+
+	// 过了软有效期，后台清理不会删除条目
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.GetFresh("key"); ok {
+		t.Errorf("Expected stale value to be a miss for GetFresh")
+	}
+	if v, stale, ok := cache.GetAllowStale("key"); !ok || !stale || v != 1 {
+		t.Errorf("Expected stale value, got %v, stale: %v, ok: %v", v, stale, ok)
+	}
+	if v, ok := cache.Get("key"); !ok || v != 1 {
+		t.Errorf("Expected Get to return the stale value, got %v, ok: %v", v, ok)
+	}
+	if n := cache.Len(); n != 1 {
+		t.Errorf("Expected sweeper to keep soft-expired entry, got %d entries", n)
+	}
+
+	// 过了硬有效期，条目被清理
+	time.Sleep(50 * time.Millisecond)
+	if n := cache.Len(); n != 0 {
+		t.Errorf("Expected sweeper to remove hard-expired entry, got %d entries", n)
+	}
+	if _, _, ok := cache.GetAllowStale("key"); ok {
+		t.Errorf("Expected hard-expired entry to be gone")
+	}
+}
+
+func TestSetExpiresRefresh(t *testing.T) {
+	var loads atomic.Int32
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			return int(loads.Add(1)) + 1, nil
+		},
+	})
+	defer cache.Close()
+	cache.SetExpires("key", 1, 5*time.Millisecond, time.Minute)
+	time.Sleep(10 * time.Millisecond)
+
+	// 变旧的条目触发后台刷新，刷新完成前返回旧值
+	if v, _ := cache.Get("key"); v != 1 {
+		t.Errorf("Expected stale value while refreshing, got %v", v)
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, ok := cache.GetFresh("key"); ok && v == 2 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("Expected stale entry to be refreshed, loads: %d", loads.Load())
+}
+
+func TestSetExpiresNoSoft(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	// soft 小于等于0时忽略 hard，条目不会变旧也不会失效
+	cache.SetExpires("key", 1, 0, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if v, stale, ok := cache.GetAllowStale("key"); !ok || stale || v != 1 {
+		t.Errorf("Expected non-expiring fresh value, got %v, stale: %v, ok: %v", v, stale, ok)
+	}
+}
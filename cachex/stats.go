@@ -0,0 +1,57 @@
+package cachex
+
+import "sync/atomic"
+
+// CacheStats 缓存的统计数据快照
+type CacheStats struct {
+	Hits         uint64 // 命中次数
+	Misses       uint64 // 未命中次数
+	Loads        uint64 // 加载函数执行次数
+	LoadFailures uint64 // 加载失败次数
+	Evictions    uint64 // 因过期或超出容量被移除的条目数，不包含手动删除
+	Size         int    // 当前未失效的条目数，与 Len 相同，不包含 SetNegative 写入的占位
+}
+
+// HitRatio 返回命中率，没有任何访问时返回0
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type cacheStats struct {
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	loads        atomic.Uint64
+	loadFailures atomic.Uint64
+	evictions    atomic.Uint64
+	capacity     atomic.Uint64 // 因超出容量被淘汰的条目数，用于 AutoScale
+}
+
+func (s *cacheStats) reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.loads.Store(0)
+	s.loadFailures.Store(0)
+	s.evictions.Store(0)
+	s.capacity.Store(0)
+}
+
+// Stats 返回缓存的统计数据
+func (c *BaseCache[K, V]) Stats() CacheStats {
+	return CacheStats{
+		Hits:         c.stats.hits.Load(),
+		Misses:       c.stats.misses.Load(),
+		Loads:        c.stats.loads.Load(),
+		LoadFailures: c.stats.loadFailures.Load(),
+		Evictions:    c.stats.evictions.Load(),
+		Size:         c.Len(),
+	}
+}
+
+// ResetStats 清零所有计数器，不影响缓存中的条目
+func (c *BaseCache[K, V]) ResetStats() {
+	c.stats.reset()
+}
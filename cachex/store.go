@@ -0,0 +1,97 @@
+package cachex
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Store 缓存的外部存储，例如 Redis、磁盘
+// 值以字节形式保存，ttl 小于等于0时永不过期
+type Store interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+	// TTL 返回键的剩余有效期，键不存在时 ok 为 false，永不过期时返回的 ttl 小于0
+	TTL(ctx context.Context, key string) (ttl time.Duration, ok bool, err error)
+}
+
+// Codec 在缓存值与 Store 中的字节之间转换
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec 使用 encoding/json 编解码
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// MemoryStore 基于内存的 Store 实现，主要用于测试和单进程场景
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]memoryStoreItem
+}
+
+type memoryStoreItem struct {
+	value  []byte
+	expire time.Time
+}
+
+func (i memoryStoreItem) expired(now time.Time) bool {
+	return !i.expire.IsZero() && !i.expire.After(now)
+}
+
+// NewMemoryStore 创建一个内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[string]memoryStoreItem)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	item, ok := s.items[key]
+	s.mu.RUnlock()
+	if !ok || item.expired(time.Now()) {
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	item := memoryStoreItem{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		item.expire = time.Now().Add(ttl)
+	}
+	s.mu.Lock()
+	s.items[key] = item
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Del(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.items, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) TTL(ctx context.Context, key string) (time.Duration, bool, error) {
+	s.mu.RLock()
+	item, ok := s.items[key]
+	s.mu.RUnlock()
+	now := time.Now()
+	if !ok || item.expired(now) {
+		return 0, false, nil
+	}
+	if item.expire.IsZero() {
+		return -1, true, nil
+	}
+	return item.expire.Sub(now), true, nil
+}
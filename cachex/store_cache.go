@@ -0,0 +1,153 @@
+package cachex
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StoreCache 在外部存储之上提供与 BaseCache 相同的一次性缓存语义，可以在多个进程间共享
+// 所有键都带有 Prefix 前缀，键的有效期不会超过整个缓存的生命周期
+type StoreCache[T any] struct {
+	store    Store
+	opts     StoreCacheOption
+	deadline time.Time
+	timer    *time.Timer
+	closed   atomic.Bool
+	once     sync.Once
+}
+
+type StoreCacheOption struct {
+	// Prefix 所有键的前缀，用于在共享存储中区分不同的缓存
+	Prefix string
+	// Expire 整个缓存的生命周期，到期后缓存不再可用，小于等于0时永不销毁
+	Expire           time.Duration
+	DefaultKeyExpire time.Duration
+	Destroy          func()
+	// Codec 值的编解码方式，为空时使用 JSONCodec
+	Codec Codec
+	// Timeout 每次访问存储的超时时间，小于等于0时不设超时
+	Timeout time.Duration
+	// OnError 访问存储或编解码失败时的回调，Cache 接口的方法无法返回错误
+	OnError func(err error)
+}
+
+// NewStoreCache 创建一个基于外部存储的缓存
+func NewStoreCache[T any](store Store, opts StoreCacheOption) *StoreCache[T] {
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec{}
+	}
+	c := &StoreCache[T]{store: store, opts: opts}
+	if opts.Expire > 0 {
+		c.deadline = time.Now().Add(opts.Expire)
+		c.timer = time.AfterFunc(opts.Expire, c.destroy)
+	}
+	return c
+}
+
+// Close 提前销毁缓存并执行 Destroy 回调，已写入存储的键会在各自的有效期后过期
+func (c *StoreCache[T]) Close() {
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.destroy()
+}
+
+func (c *StoreCache[T]) destroy() {
+	c.once.Do(func() {
+		c.closed.Store(true)
+		if c.opts.Destroy != nil {
+			c.opts.Destroy()
+		}
+	})
+}
+
+// Closed 判断缓存是否已经销毁
+func (c *StoreCache[T]) Closed() bool {
+	return c.closed.Load()
+}
+
+func (c *StoreCache[T]) Get(key string) (T, bool) {
+	var value T
+	if c.closed.Load() {
+		return value, false
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	data, ok, err := c.store.Get(ctx, c.opts.Prefix+key)
+	if err != nil || !ok {
+		c.onError(err)
+		return value, false
+	}
+	if err := c.opts.Codec.Unmarshal(data, &value); err != nil {
+		c.onError(err)
+		return value, false
+	}
+	return value, true
+}
+
+func (c *StoreCache[T]) Set(key string, value T) {
+	c.SetExpire(key, value, c.opts.DefaultKeyExpire)
+}
+
+func (c *StoreCache[T]) SetExpire(key string, value T, expire time.Duration) {
+	if c.closed.Load() {
+		return
+	}
+	// 键的有效期不能超过缓存剩余的生命周期
+	if !c.deadline.IsZero() {
+		remain := time.Until(c.deadline)
+		if remain <= 0 {
+			return
+		}
+		if expire <= 0 || expire > remain {
+			expire = remain
+		}
+	}
+	data, err := c.opts.Codec.Marshal(value)
+	if err != nil {
+		c.onError(err)
+		return
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	c.onError(c.store.Set(ctx, c.opts.Prefix+key, data, expire))
+}
+
+func (c *StoreCache[T]) Del(key string) {
+	if c.closed.Load() {
+		return
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	c.onError(c.store.Del(ctx, c.opts.Prefix+key))
+}
+
+// TTL 返回键的剩余有效期，永不过期时返回的值小于0
+func (c *StoreCache[T]) TTL(key string) (time.Duration, bool) {
+	if c.closed.Load() {
+		return 0, false
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	ttl, ok, err := c.store.TTL(ctx, c.opts.Prefix+key)
+	if err != nil {
+		c.onError(err)
+		return 0, false
+	}
+	return ttl, ok
+}
+
+func (c *StoreCache[T]) context() (context.Context, context.CancelFunc) {
+	if c.opts.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.opts.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func (c *StoreCache[T]) onError(err error) {
+	if err != nil && c.opts.OnError != nil {
+		c.opts.OnError(err)
+	}
+}
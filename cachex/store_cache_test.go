@@ -0,0 +1,65 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStoreCache(t *testing.T) {
+	store := NewMemoryStore()
+	destroyed := make(chan struct{})
+	var _ Cache[int] = (*StoreCache[int])(nil)
+	cache := NewStoreCache[int](store, StoreCacheOption{
+		Prefix: "test:",
+		Expire: 50 * time.Millisecond,
+		Destroy: func() {
+			close(destroyed)
+		},
+	})
+
+	cache.Set("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %v, ok: %v", v, ok)
+	}
+	if _, ok, _ := store.Get(context.Background(), "test:a"); !ok {
+		t.Errorf("Expected key to be stored with prefix")
+	}
+	// 键的有效期被限制在缓存的生命周期内
+	if ttl, ok := cache.TTL("a"); !ok || ttl <= 0 || ttl > 50*time.Millisecond {
+		t.Errorf("Expected ttl within cache lifetime, got %v, ok: %v", ttl, ok)
+	}
+
+	cache.Del("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected 'a' to be deleted")
+	}
+
+	select {
+	case <-destroyed:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Destroy to be called after Expire")
+	}
+	cache.Set("b", 2)
+	if _, ok := cache.Get("b"); ok || !cache.Closed() {
+		t.Errorf("Expected closed cache to ignore writes")
+	}
+}
+
+func TestMemoryStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Set(ctx, "a", []byte("1"), 0)
+	store.Set(ctx, "b", []byte("2"), 10*time.Millisecond)
+
+	if ttl, ok, _ := store.TTL(ctx, "a"); !ok || ttl >= 0 {
+		t.Errorf("Expected negative ttl for key without expire, got %v, ok: %v", ttl, ok)
+	}
+	if ttl, ok, _ := store.TTL(ctx, "b"); !ok || ttl <= 0 {
+		t.Errorf("Expected positive ttl, got %v, ok: %v", ttl, ok)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok, _ := store.TTL(ctx, "b"); ok {
+		t.Errorf("Expected 'b' to be expired")
+	}
+}
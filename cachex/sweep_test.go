@@ -0,0 +1,72 @@
+package cachex_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/llyb120/gotool/cachex"
+	"github.com/llyb120/gotool/cachex/clocktest"
+)
+
+// advanceUntil 持续推进时钟直到 done 被关闭或达到 limit，返回推进的总时长
+func advanceUntil(clock *clocktest.Clock, step, limit time.Duration, done <-chan struct{}) time.Duration {
+	var total time.Duration
+	for total < limit {
+		select {
+		case <-done:
+			return total
+		case <-time.After(2 * time.Millisecond):
+		}
+		clock.Advance(step)
+		total += step
+	}
+	return total
+}
+
+func TestAdaptiveSweep(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	swept := make(chan struct{})
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:            clock,
+		CheckInterval:    time.Second,
+		MaxCheckInterval: time.Hour,
+		OnEvict: func(key string, value int, reason cachex.EvictReason) {
+			if reason == cachex.EvictExpired {
+				close(swept)
+			}
+		},
+	})
+	defer cache.Close()
+
+	// 空闲时第一次唤醒后间隔被拉长到 MaxCheckInterval
+	advanceUntil(clock, time.Second, 3*time.Second, nil)
+	cache.SetExpire("a", 1, 10*time.Second)
+	// 写入即将到期的条目会提前清理，而不是等到1小时后
+	if total := advanceUntil(clock, time.Second, time.Minute, swept); total < 9*time.Second || total >= time.Minute {
+		t.Fatalf("Expected sweeper to wake around the entry's expiry, got %v", total)
+	}
+}
+
+func TestAdaptiveSweepBacksOff(t *testing.T) {
+	clock := clocktest.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	swept := make(chan struct{})
+	cache := cachex.NewBaseCache(cachex.OnceCacheOption[string, int]{
+		Clock:            clock,
+		CheckInterval:    time.Second,
+		MaxCheckInterval: time.Hour,
+		OnEvict: func(key string, value int, reason cachex.EvictReason) {
+			close(swept)
+		},
+	})
+	defer cache.Close()
+
+	cache.SetExpire("a", 1, 10*time.Minute)
+	// 清理间隔按最早到期的条目拉长，期间不会每秒唤醒
+	advanceUntil(clock, time.Second, 5*time.Second, nil)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("Expected 'a' to be alive")
+	}
+	if total := advanceUntil(clock, 30*time.Second, 2*time.Hour, swept); total < 9*time.Minute || total > 12*time.Minute {
+		t.Fatalf("Expected 'a' to be swept around 10m, got %v", total)
+	}
+}
@@ -0,0 +1,83 @@
+package cachex
+
+// SetWithTags 写入键值对并为其打上标签，之后可以通过 InvalidateTag 删除带有某个标签的所有条目
+// 标签随条目一起保存，再次写入同一个键时以最新一次写入的标签为准，使用 Set 等不带标签的方法覆盖后条目不再属于任何标签
+func (c *BaseCache[K, V]) SetWithTags(key K, value V, tags ...string) {
+	s := c.shard(key)
+	s.lock()
+	defer s.unlock()
+	if c.closed.Load() {
+		return
+	}
+	expire := c.jitter(c.defaultExpire(key, value))
+	if c.writer != nil && !c.writer.set(key, value, expire) {
+		return
+	}
+	item := c.newItem(key, value, expire)
+	item.tags = append([]string(nil), tags...)
+	s.setItem(key, item)
+	s.invalidate(key)
+}
+
+// InvalidateTag 删除带有该标签的所有条目并返回删除的数量，耗时与受影响的条目数成正比
+// 被删除的条目以 EvictDeleted 通知 OnEvict，并逐个键发布失效通知
+func (c *BaseCache[K, V]) InvalidateTag(tag string) int {
+	n := 0
+	for _, s := range c.shards {
+		s.lock()
+		for key := range s.tags[tag] {
+			if c.writer != nil && !c.closed.Load() && !c.writer.del(key) {
+				continue
+			}
+			s.removeItem(key, s.cache[key], EvictDeleted)
+			s.invalidate(key)
+			n++
+		}
+		s.unlock()
+	}
+	return n
+}
+
+// Tags 返回键当前的标签，键不存在时返回 false
+func (c *BaseCache[K, V]) Tags(key K) ([]string, bool) {
+	s := c.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	item, ok := s.cache[key]
+	if !ok || item.expired(c.now()) {
+		return nil, false
+	}
+	return append([]string(nil), item.tags...), true
+}
+
+// 以下方法均需在持有锁的情况下调用
+
+// tag 把条目登记到它的每个标签下
+func (s *cacheShard[K, V]) tag(key K, item *cacheItemWrapper[K, V]) {
+	if len(item.tags) == 0 {
+		return
+	}
+	if s.tags == nil {
+		s.tags = make(map[string]map[K]struct{})
+	}
+	for _, tag := range item.tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[K]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+// untag 从标签索引中移除条目，标签下没有其他键时删除该标签
+func (s *cacheShard[K, V]) untag(key K, item *cacheItemWrapper[K, V]) {
+	for _, tag := range item.tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cachex
+
+import "testing"
+
+func TestInvalidateTag(t *testing.T) {
+	var deleted []string
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		OnEvict: func(key string, value int, reason EvictReason) {
+			if reason == EvictDeleted {
+				deleted = append(deleted, key)
+			}
+		},
+	})
+	defer cache.Close()
+	cache.SetWithTags("t1:a", 1, "tenant:1", "table:users")
+	cache.SetWithTags("t1:b", 2, "tenant:1")
+	cache.SetWithTags("t2:a", 3, "tenant:2", "table:users")
+	cache.Set("plain", 4)
+
+	if tags, ok := cache.Tags("t1:a"); !ok || len(tags) != 2 {
+		t.Errorf("Expected 2 tags, got %v, ok: %v", tags, ok)
+	}
+	if n := cache.InvalidateTag("tenant:1"); n != 2 {
+		t.Errorf("Expected 2 invalidated entries, got %d", n)
+	}
+	if len(deleted) != 2 {
+		t.Errorf("Expected OnEvict for deleted entries, got %v", deleted)
+	}
+	for _, key := range []string{"t1:a", "t1:b"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("Expected %s to be invalidated", key)
+		}
+	}
+	// 删除的条目同时从其他标签中移除
+	if n := cache.InvalidateTag("table:users"); n != 1 {
+		t.Errorf("Expected 1 invalidated entry, got %d", n)
+	}
+	if _, ok := cache.Get("plain"); !ok {
+		t.Errorf("Expected untagged entry to remain")
+	}
+	if n := cache.InvalidateTag("missing"); n != 0 {
+		t.Errorf("Expected no entries for unknown tag, got %d", n)
+	}
+}
+
+func TestSetWithTagsOverwrite(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{MaxEntries: 2})
+	defer cache.Close()
+	cache.SetWithTags("a", 1, "x")
+	cache.SetWithTags("a", 2, "y")
+	if n := cache.InvalidateTag("x"); n != 0 {
+		t.Errorf("Expected rewritten entry to leave its old tag, got %d", n)
+	}
+	cache.Set("a", 3)
+	if n := cache.InvalidateTag("y"); n != 0 {
+		t.Errorf("Expected untagged overwrite to clear tags, got %d", n)
+	}
+
+	// 被容量淘汰的条目不再出现在标签中
+	cache.SetWithTags("b", 1, "z")
+	cache.SetWithTags("c", 1, "z")
+	cache.SetWithTags("d", 1, "z")
+	if n := cache.InvalidateTag("z"); n != 2 {
+		t.Errorf("Expected 2 remaining tagged entries, got %d", n)
+	}
+}
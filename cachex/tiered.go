@@ -0,0 +1,92 @@
+package cachex
+
+import (
+	"context"
+	"time"
+)
+
+// Tiered 两级缓存，先查本地的 L1，未命中时再查较慢的 L2 存储
+// L2 命中后回填 L1，写入时同时写入两级，两级的有效期相互独立
+type Tiered[T any] struct {
+	l1   *BaseCache[string, T]
+	l2   Store
+	opts TieredOption
+}
+
+type TieredOption struct {
+	// L2Expire 写入 L2 的有效期，小于等于0时永不过期，L1 的有效期由其自身的 DefaultKeyExpire 决定
+	L2Expire time.Duration
+	// Codec 值与 L2 字节之间的编解码方式，为空时使用 JSONCodec
+	Codec Codec
+}
+
+// NewTiered 使用已创建的 L1 缓存和 L2 存储创建两级缓存
+func NewTiered[T any](l1 *BaseCache[string, T], l2 Store, opts TieredOption) *Tiered[T] {
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec{}
+	}
+	return &Tiered[T]{l1: l1, l2: l2, opts: opts}
+}
+
+// Get 依次查找 L1 和 L2，L2 命中时回填 L1
+func (t *Tiered[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	if value, ok := t.l1.Get(key); ok {
+		return value, true, nil
+	}
+	value, ok, err := t.getL2(ctx, key)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	t.l1.Set(key, value)
+	return value, true, nil
+}
+
+// Set 先写入 L2 再写入 L1，L2 写入失败时不会修改 L1
+func (t *Tiered[T]) Set(ctx context.Context, key string, value T) error {
+	if err := t.setL2(ctx, key, value); err != nil {
+		return err
+	}
+	t.l1.Set(key, value)
+	return nil
+}
+
+// Del 同时删除两级中的键
+func (t *Tiered[T]) Del(ctx context.Context, key string) error {
+	t.l1.Del(key)
+	return t.l2.Del(ctx, key)
+}
+
+// GetOrSetFuncCtx 依次查找 L1 和 L2，都未命中时调用 fn 加载并写入两级
+// 同一个键的并发加载在 L1 中合并为一次
+func (t *Tiered[T]) GetOrSetFuncCtx(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	return t.l1.GetOrSetFuncCtx(ctx, key, func(ctx context.Context) (T, error) {
+		value, ok, err := t.getL2(ctx, key)
+		if err != nil || ok {
+			return value, err
+		}
+		if value, err = fn(ctx); err != nil {
+			return value, err
+		}
+		return value, t.setL2(ctx, key, value)
+	})
+}
+
+func (t *Tiered[T]) getL2(ctx context.Context, key string) (T, bool, error) {
+	var value T
+	data, ok, err := t.l2.Get(ctx, key)
+	if err != nil || !ok {
+		return value, false, err
+	}
+	if err := t.opts.Codec.Unmarshal(data, &value); err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+func (t *Tiered[T]) setL2(ctx context.Context, key string, value T) error {
+	data, err := t.opts.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return t.l2.Set(ctx, key, data, t.opts.L2Expire)
+}
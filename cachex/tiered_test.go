@@ -0,0 +1,63 @@
+package cachex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+	l1 := NewBaseCache(OnceCacheOption[string, user]{
+		DefaultKeyExpire: 20 * time.Millisecond,
+	})
+	defer l1.Close()
+	l2 := NewMemoryStore()
+	cache := NewTiered(l1, l2, TieredOption{L2Expire: time.Hour})
+
+	if err := cache.Set(ctx, "a", user{Name: "a", Age: 1}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, ok, _ := l2.Get(ctx, "a"); !ok {
+		t.Errorf("Expected Set to write through to L2")
+	}
+
+	// L1 过期后从 L2 读取并回填
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := l1.Get("a"); ok {
+		t.Errorf("Expected L1 entry to be expired")
+	}
+	v, ok, err := cache.Get(ctx, "a")
+	if err != nil || !ok || v.Name != "a" || v.Age != 1 {
+		t.Errorf("Expected value from L2, got %+v, ok: %v, err: %v", v, ok, err)
+	}
+	if _, ok := l1.Get("a"); !ok {
+		t.Errorf("Expected L2 hit to populate L1")
+	}
+
+	// 两级都未命中时加载并写入两级
+	calls := 0
+	load := func(ctx context.Context) (user, error) {
+		calls++
+		return user{Name: "b"}, nil
+	}
+	if v, err := cache.GetOrSetFuncCtx(ctx, "b", load); err != nil || v.Name != "b" {
+		t.Errorf("Expected loaded value, got %+v, err: %v", v, err)
+	}
+	l1.Del("b")
+	if v, err := cache.GetOrSetFuncCtx(ctx, "b", load); err != nil || v.Name != "b" || calls != 1 {
+		t.Errorf("Expected value from L2 without reloading, got %+v, calls: %d, err: %v", v, calls, err)
+	}
+
+	if err := cache.Del(ctx, "a"); err != nil {
+		t.Errorf("Del failed: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Errorf("Expected 'a' to be deleted from both tiers")
+	}
+}
@@ -0,0 +1,277 @@
+package cachex
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+)
+
+// TinyLFU 采用 W-TinyLFU 策略的协程安全缓存
+// 新条目先进入容量约为 1% 的窗口 LRU，被挤出窗口后需要与主区的淘汰候选比较访问频率，
+// 频率更高者才能留在主区；主区为分段 LRU（试用区 20%，保护区 80%），
+// 访问频率由 Count-Min Sketch 近似统计并周期性衰减，对热点集中的负载命中率明显高于普通 LRU
+type TinyLFU[K comparable, V any] struct {
+	mu        sync.Mutex
+	items     map[K]*list.Element
+	window    *list.List
+	probation *list.List
+	protected *list.List
+	sketch    *cmSketch
+	seed      maphash.Seed
+	opts      TinyLFUOption[K, V]
+
+	windowCap    int
+	probationCap int
+	protectedCap int
+	evicted      []evictedEntry[K, V]
+}
+
+type TinyLFUOption[K comparable, V any] struct {
+	// Capacity 最大条目数，必须大于0
+	Capacity int
+	// OnEvict 条目被移除时的回调，在释放锁之后调用
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+const (
+	segmentWindow = iota
+	segmentProbation
+	segmentProtected
+)
+
+type tinyLFUEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint64
+	segment int
+}
+
+// NewTinyLFU 创建一个 TinyLFU 缓存，Capacity 小于1时按1处理
+func NewTinyLFU[K comparable, V any](opts TinyLFUOption[K, V]) *TinyLFU[K, V] {
+	if opts.Capacity < 1 {
+		opts.Capacity = 1
+	}
+	windowCap := opts.Capacity / 100
+	if windowCap < 1 {
+		windowCap = 1
+	}
+	mainCap := opts.Capacity - windowCap
+	protectedCap := mainCap * 8 / 10
+	return &TinyLFU[K, V]{
+		items:        make(map[K]*list.Element),
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		sketch:       newCMSketch(opts.Capacity),
+		seed:         maphash.MakeSeed(),
+		opts:         opts,
+		windowCap:    windowCap,
+		probationCap: mainCap - protectedCap,
+		protectedCap: protectedCap,
+	}
+}
+
+// Get 获取键对应的值，并记录一次访问
+func (c *TinyLFU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.sketch.increment(hashKey(c.seed, key))
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.touch(elem)
+	return elem.Value.(*tinyLFUEntry[K, V]).value, true
+}
+
+// Peek 获取键对应的值，不记录访问
+func (c *TinyLFU[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return elem.Value.(*tinyLFUEntry[K, V]).value, true
+}
+
+// Set 添加或更新键值对，新条目是否能长期保留取决于其访问频率
+func (c *TinyLFU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	h := hashKey(c.seed, key)
+	c.sketch.increment(h)
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*tinyLFUEntry[K, V]).value = value
+		c.touch(elem)
+		return
+	}
+
+	entry := &tinyLFUEntry[K, V]{key: key, value: value, hash: h, segment: segmentWindow}
+	c.items[key] = c.window.PushFront(entry)
+	if c.window.Len() <= c.windowCap {
+		return
+	}
+
+	// 窗口已满，最旧的条目成为进入主区的候选
+	candidate := c.window.Back()
+	if c.probation.Len()+c.protected.Len() < c.probationCap+c.protectedCap {
+		c.moveTo(candidate, c.probation, segmentProbation)
+		return
+	}
+	victim := c.probation.Back()
+	if victim == nil {
+		victim = c.protected.Back()
+	}
+	if victim == nil {
+		c.remove(candidate, EvictCapacity)
+		return
+	}
+	candidateFreq := c.sketch.estimate(candidate.Value.(*tinyLFUEntry[K, V]).hash)
+	victimFreq := c.sketch.estimate(victim.Value.(*tinyLFUEntry[K, V]).hash)
+	if candidateFreq > victimFreq {
+		c.remove(victim, EvictCapacity)
+		c.moveTo(candidate, c.probation, segmentProbation)
+	} else {
+		c.remove(candidate, EvictCapacity)
+	}
+}
+
+// Remove 删除键值对，返回键是否存在
+func (c *TinyLFU[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.remove(elem, EvictDeleted)
+	return true
+}
+
+// Len 返回条目数
+func (c *TinyLFU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// unlock 释放锁，并通知持有锁期间被移除的条目
+func (c *TinyLFU[K, V]) unlock() {
+	evicted := c.evicted
+	c.evicted = nil
+	c.mu.Unlock()
+	for _, e := range evicted {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// touch 记录一次命中：窗口和保护区内移到队首，试用区的条目晋升到保护区
+func (c *TinyLFU[K, V]) touch(elem *list.Element) {
+	entry := elem.Value.(*tinyLFUEntry[K, V])
+	switch entry.segment {
+	case segmentWindow:
+		c.window.MoveToFront(elem)
+	case segmentProtected:
+		c.protected.MoveToFront(elem)
+	case segmentProbation:
+		c.moveTo(elem, c.protected, segmentProtected)
+		if c.protected.Len() > c.protectedCap {
+			c.moveTo(c.protected.Back(), c.probation, segmentProbation)
+		}
+	}
+}
+
+// moveTo 将条目移动到指定分段的队首
+func (c *TinyLFU[K, V]) moveTo(elem *list.Element, dst *list.List, segment int) {
+	entry := c.segment(elem).Remove(elem).(*tinyLFUEntry[K, V])
+	entry.segment = segment
+	c.items[entry.key] = dst.PushFront(entry)
+}
+
+func (c *TinyLFU[K, V]) remove(elem *list.Element, reason EvictReason) {
+	entry := c.segment(elem).Remove(elem).(*tinyLFUEntry[K, V])
+	delete(c.items, entry.key)
+	if c.opts.OnEvict != nil {
+		c.evicted = append(c.evicted, evictedEntry[K, V]{key: entry.key, value: entry.value, reason: reason})
+	}
+}
+
+func (c *TinyLFU[K, V]) segment(elem *list.Element) *list.List {
+	switch elem.Value.(*tinyLFUEntry[K, V]).segment {
+	case segmentWindow:
+		return c.window
+	case segmentProbation:
+		return c.probation
+	default:
+		return c.protected
+	}
+}
+
+// cmSketch 4 行的 Count-Min Sketch，计数器上限为 15
+// 累计次数达到容量的 10 倍时所有计数器减半，使频率统计偏向近期访问
+type cmSketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	resetAt   int
+}
+
+func newCMSketch(capacity int) *cmSketch {
+	width := 16
+	for width < capacity {
+		width <<= 1
+	}
+	s := &cmSketch{
+		mask:    uint64(width - 1),
+		resetAt: capacity * 10,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *cmSketch) index(h uint64, row int) uint64 {
+	// 双重哈希为每一行生成不同的下标
+	h2 := h>>32 | 1
+	return (h + uint64(row)*h2) & s.mask
+}
+
+func (s *cmSketch) increment(h uint64) {
+	for i := range s.rows {
+		idx := s.index(h, i)
+		if s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+func (s *cmSketch) estimate(h uint64) uint8 {
+	min := uint8(15)
+	for i := range s.rows {
+		if v := s.rows[i][s.index(h, i)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func (s *cmSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
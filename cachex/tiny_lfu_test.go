@@ -0,0 +1,80 @@
+package cachex
+
+import (
+	"testing"
+)
+
+func TestTinyLFU(t *testing.T) {
+	cache := NewTinyLFU(TinyLFUOption[int, int]{
+		Capacity: 100,
+	})
+
+	// 热点键被频繁访问
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 10; i++ {
+			cache.Set(i, i)
+			cache.Get(i)
+		}
+	}
+	// 大量只访问一次的键扫过缓存，数量是容量的数倍
+	for i := 1000; i < 1500; i++ {
+		cache.Set(i, i)
+	}
+
+	if n := cache.Len(); n > 100 {
+		t.Errorf("Expected at most 100 entries, got %d", n)
+	}
+	for i := 0; i < 10; i++ {
+		if v, ok := cache.Peek(i); !ok || v != i {
+			t.Errorf("Expected hot key %d to survive the scan, got %v, ok: %v", i, v, ok)
+		}
+	}
+
+	if !cache.Remove(1) || cache.Remove(1) {
+		t.Errorf("Expected Remove to report existence")
+	}
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected key 1 to be removed")
+	}
+}
+
+func TestTinyLFUOnEvict(t *testing.T) {
+	evicted := 0
+	cache := NewTinyLFU(TinyLFUOption[int, int]{
+		Capacity: 10,
+		OnEvict: func(key int, value int, reason EvictReason) {
+			if reason == EvictCapacity {
+				evicted++
+			}
+		},
+	})
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i)
+	}
+	if cache.Len() != 10 {
+		t.Errorf("Expected 10 entries, got %d", cache.Len())
+	}
+	if evicted != 90 {
+		t.Errorf("Expected 90 evictions, got %d", evicted)
+	}
+}
+
+func TestCMSketch(t *testing.T) {
+	s := newCMSketch(64)
+	for i := 0; i < 5; i++ {
+		s.increment(42)
+	}
+	if v := s.estimate(42); v < 5 {
+		t.Errorf("Expected estimate at least 5, got %d", v)
+	}
+	for i := 0; i < 100; i++ {
+		s.increment(42)
+	}
+	if v := s.estimate(42); v > 15 {
+		t.Errorf("Expected counter to saturate at 15, got %d", v)
+	}
+	s.reset()
+	if v := s.estimate(42); v > 8 {
+		t.Errorf("Expected counter to be halved, got %d", v)
+	}
+}
@@ -0,0 +1,75 @@
+package cachex
+
+import "sort"
+
+// Update 同时锁住 keys 涉及的所有分片，以这些键当前的值调用 fn，并把 fn 返回的结果一次性写回
+// view 中只包含存在且未失效的键，返回值即这些键的新状态：返回值中的键按 DefaultKeyExpire 写入，
+// keys 中不在返回值里的键被删除，不在 keys 中的键会被忽略，返回 nil 时不做任何修改
+// 写回完成前其他读写都无法看到这些键的中间状态，例如索引和它指向的条目总是同时更新
+// fn 在锁内执行，不能再调用该缓存的方法
+func (c *BaseCache[K, V]) Update(keys []K, fn func(view map[K]V) map[K]V) {
+	groups := c.groupKeys(keys)
+	shards := make([]*cacheShard[K, V], 0, len(groups))
+	for s := range groups {
+		shards = append(shards, s)
+	}
+	// 按分片下标的固定顺序加锁，避免并发的 Update 互相等待
+	index := make(map[*cacheShard[K, V]]int, len(c.shards))
+	for i, s := range c.shards {
+		index[s] = i
+	}
+	sort.Slice(shards, func(i, j int) bool { return index[shards[i]] < index[shards[j]] })
+	for _, s := range shards {
+		s.lock()
+	}
+	defer func() {
+		// 先释放所有分片的锁再统一通知，OnEvict 中访问同一批的其他分片也不会死锁
+		var evicted []evictedEntry[K, V]
+		var invalid []string
+		for i := len(shards) - 1; i >= 0; i-- {
+			e, keys := shards[i].release()
+			evicted = append(evicted, e...)
+			invalid = append(invalid, keys...)
+		}
+		c.notify(evicted, invalid)
+	}()
+	if c.closed.Load() {
+		return
+	}
+
+	now := c.now()
+	view := make(map[K]V, len(keys))
+	for s, group := range groups {
+		for _, key := range group {
+			if item := s.getItem(key, now); item != nil && !item.negative {
+				view[key] = c.clone(item.value)
+			}
+		}
+	}
+	result := fn(view)
+	if result == nil {
+		return
+	}
+	for s, group := range groups {
+		for _, key := range group {
+			if value, ok := result[key]; ok {
+				expire := c.jitter(c.defaultExpire(key, value))
+				if c.writer != nil && !c.writer.set(key, value, expire) {
+					continue
+				}
+				s.setItem(key, c.newItem(key, value, expire))
+				s.invalidate(key)
+				continue
+			}
+			item, ok := s.cache[key]
+			if !ok {
+				continue
+			}
+			if c.writer != nil && !c.writer.del(key) {
+				continue
+			}
+			s.removeItem(key, item, EvictDeleted)
+			s.invalidate(key)
+		}
+	}
+}
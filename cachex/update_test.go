@@ -0,0 +1,137 @@
+package cachex
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdate(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Shards: 8})
+	defer cache.Close()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	cache.Update([]string{"a", "b", "c"}, func(view map[string]int) map[string]int {
+		if len(view) != 2 || view["a"] != 1 || view["b"] != 2 {
+			t.Errorf("Unexpected view: %v", view)
+		}
+		return map[string]int{"a": view["a"] + view["b"], "c": 3, "ignored": 4}
+	})
+	if v, _ := cache.Get("a"); v != 3 {
+		t.Errorf("Expected a=3, got %v", v)
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("Expected b to be deleted")
+	}
+	if v, _ := cache.Get("c"); v != 3 {
+		t.Errorf("Expected c=3, got %v", v)
+	}
+	if _, ok := cache.Get("ignored"); ok {
+		t.Errorf("Expected keys outside of keys to be ignored")
+	}
+
+	cache.Update([]string{"a"}, func(view map[string]int) map[string]int { return nil })
+	if v, _ := cache.Get("a"); v != 3 {
+		t.Errorf("Expected nil result to leave a unchanged, got %v", v)
+	}
+}
+
+func TestUpdateAtomic(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{Shards: 8})
+	defer cache.Close()
+	keys := make([]string, 8)
+	for i := range keys {
+		keys[i] = fmt.Sprint("k", i)
+		cache.Set(keys[i], 0)
+	}
+
+	// 所有键总是同时更新，读取到的总和只能是键数的整数倍
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				cache.Update(keys, func(view map[string]int) map[string]int {
+					for k := range view {
+						view[k]++
+					}
+					return view
+				})
+			}
+		}()
+	}
+	for i := 0; i < 200; i++ {
+		var sum int
+		cache.Update(keys, func(view map[string]int) map[string]int {
+			for _, v := range view {
+				sum += v
+			}
+			return nil
+		})
+		if sum%len(keys) != 0 {
+			t.Fatalf("Observed partial update, sum %d", sum)
+		}
+	}
+	wg.Wait()
+	if v, _ := cache.Get("k0"); v != 800 {
+		t.Errorf("Expected 800 updates, got %v", v)
+	}
+}
+
+func TestUpdateOnEvictAfterUnlock(t *testing.T) {
+	var cache *BaseCache[string, int]
+	var kept, removed string
+	got := make(chan int, 1)
+	cache = NewBaseCache(OnceCacheOption[string, int]{
+		Shards: 8,
+		OnEvict: func(key string, value int, reason EvictReason) {
+			if reason != EvictDeleted {
+				return
+			}
+			// 读取同一批中另一个分片的键，回调时锁应已全部释放
+			v, _ := cache.Get(kept)
+			got <- v
+		},
+	})
+
+	// 被删除的键位于下标更大的分片，按逆序解锁时它的分片最先释放
+	index := func(key string) int {
+		for i, s := range cache.shards {
+			if s == cache.shard(key) {
+				return i
+			}
+		}
+		return -1
+	}
+	kept = "k0"
+	for i := 1; removed == ""; i++ {
+		key := fmt.Sprint("k", i)
+		if a, b := index(kept), index(key); a < b {
+			removed = key
+		} else if a > b {
+			kept, removed = key, kept
+		}
+	}
+	cache.Set(kept, 1)
+	cache.Set(removed, 2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Update([]string{kept, removed}, func(view map[string]int) map[string]int {
+			return map[string]int{kept: view[kept]}
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Update deadlocked while notifying OnEvict")
+	}
+	cache.Close()
+	if v := <-got; v != 1 {
+		t.Errorf("Expected OnEvict to read %s=1, got %v", kept, v)
+	}
+}
@@ -0,0 +1,27 @@
+package cachex
+
+import "errors"
+
+// ErrNotModified 条目自指定版本以来没有变化
+var ErrNotModified = errors.New("cachex: not modified")
+
+// Version 返回键当前的版本号，每次写入或修改值都会分配更大的版本号
+func (c *BaseCache[K, V]) Version(key K) (uint64, bool) {
+	item, ok := c.lookup(key, c.now())
+	return item.version, ok
+}
+
+// GetIfChanged 版本号大于 since 时返回条目，否则返回 ErrNotModified，键不存在时返回 ErrNotFound
+// 轮询方保存上次拿到的 Entry.Version 即可只在数据变化时取回新值，since 为0时总是返回条目
+func (c *BaseCache[K, V]) GetIfChanged(key K, since uint64) (Entry[V], error) {
+	now := c.now()
+	item, ok := c.lookup(key, now)
+	c.recordAccess(key, ok)
+	if !ok {
+		return Entry[V]{}, ErrNotFound
+	}
+	if item.version <= since {
+		return Entry[V]{Version: item.version}, ErrNotModified
+	}
+	return newEntry(&item, now), nil
+}
@@ -0,0 +1,39 @@
+package cachex
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetIfChanged(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	if _, err := cache.GetIfChanged("key", 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	cache.Set("key", 1)
+	entry, err := cache.GetIfChanged("key", 0)
+	if err != nil || entry.Value != 1 || entry.Version == 0 {
+		t.Fatalf("Expected entry with version, got %+v, err: %v", entry, err)
+	}
+	if _, err := cache.GetIfChanged("key", entry.Version); !errors.Is(err, ErrNotModified) {
+		t.Errorf("Expected ErrNotModified, got %v", err)
+	}
+
+	// 其他键的写入不影响该键，修改值或重新写入都会增大版本号
+	cache.Set("other", 1)
+	if v, _ := cache.Version("key"); v != entry.Version {
+		t.Errorf("Expected version %d, got %d", entry.Version, v)
+	}
+	Increment(cache, "key", 1)
+	next, err := cache.GetIfChanged("key", entry.Version)
+	if err != nil || next.Value != 2 || next.Version <= entry.Version {
+		t.Errorf("Expected newer entry, got %+v, err: %v", next, err)
+	}
+	cache.Del("key")
+	cache.Set("key", 2)
+	if v, _ := cache.Version("key"); v <= next.Version {
+		t.Errorf("Expected version to keep increasing after delete, got %d", v)
+	}
+}
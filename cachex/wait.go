@@ -0,0 +1,49 @@
+package cachex
+
+import "context"
+
+// keyWaiter 等待同一个键写入的协程共享一个通道，n 为等待者数量，全部放弃等待时删除
+type keyWaiter struct {
+	ch chan struct{}
+	n  int
+}
+
+// GetOrWait 获取键的值，不存在时阻塞等待其他协程写入该键
+// ctx 取消时返回 ctx 的错误，缓存销毁时返回 ErrNotFound
+func (c *BaseCache[K, V]) GetOrWait(ctx context.Context, key K) (V, error) {
+	var zero V
+	s := c.shard(key)
+	for {
+		s.lock()
+		if item := s.getItem(key, c.now()); item != nil && !item.negative {
+			value := item.value
+			s.unlock()
+			return c.clone(value), nil
+		}
+		if c.closed.Load() {
+			s.unlock()
+			return zero, ErrNotFound
+		}
+		w, ok := s.waiters[key]
+		if !ok {
+			w = &keyWaiter{ch: make(chan struct{})}
+			s.waiters[key] = w
+		}
+		w.n++
+		s.unlock()
+
+		select {
+		case <-w.ch:
+			// 写入后可能立即被删除或淘汰，重新检查
+		case <-c.ctx.Done():
+			<-c.done
+		case <-ctx.Done():
+			s.lock()
+			if w.n--; w.n == 0 && s.waiters[key] == w {
+				delete(s.waiters, key)
+			}
+			s.unlock()
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetOrWait(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+
+	cache.Set("ready", 1)
+	if v, err := cache.GetOrWait(context.Background(), "ready"); err != nil || v != 1 {
+		t.Fatalf("Expected existing value 1, got %v, err: %v", v, err)
+	}
+
+	results := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			v, err := cache.GetOrWait(context.Background(), "result")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			results <- v
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	cache.SetNegative("result")
+	cache.Set("result", 42)
+	for i := 0; i < 2; i++ {
+		select {
+		case v := <-results:
+			if v != 42 {
+				t.Errorf("Expected 42, got %v", v)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected waiter to be woken by Set")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cache.GetOrWait(ctx, "never"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestGetOrWaitClose(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrWait(context.Background(), "key")
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cache.Close()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after close, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected waiter to return when the cache is closed")
+	}
+}
+
+func TestGetOrWaitCancelCleanup(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cache.GetOrWait(ctx, "key")
+	if n := len(cache.shard("key").waiters); n != 0 {
+		t.Errorf("Expected abandoned waiter to be removed, got %d", n)
+	}
+}
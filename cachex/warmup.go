@@ -0,0 +1,69 @@
+package cachex
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// warmEntry JSON Lines 格式中的一行，TTL 为 time.ParseDuration 能解析的时长
+type warmEntry[K comparable, V any] struct {
+	Key   K      `json:"key"`
+	Value V      `json:"value"`
+	TTL   string `json:"ttl,omitempty"`
+}
+
+// WarmFrom 从 r 载入条目，支持 Save 写出的快照和 JSON Lines 两种格式，以快照开头的标识区分，其余内容都按 JSON Lines 解析
+// JSON Lines 每行一个对象，例如 {"key": "a", "value": 1, "ttl": "10m"}，省略 ttl 时按 TTLFunc 或 DefaultKeyExpire 写入
+// 快照中的条目保留原有的过期时间，已过期的条目会被跳过，载入的条目不会写入 Store，也不会发布失效通知
+func (c *BaseCache[K, V]) WarmFrom(r io.Reader) error {
+	if c.closed.Load() {
+		return nil
+	}
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(snapshotMagic)); string(head) != snapshotMagic {
+		return c.warmJSONLines(br)
+	}
+	entries, err := readSnapshot[K, V](br)
+	if err != nil {
+		return err
+	}
+	c.restore(entries)
+	return nil
+}
+
+func (c *BaseCache[K, V]) warmJSONLines(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var entry warmEntry[K, V]
+		if err := dec.Decode(&entry); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("cachex: warmup entry %d: %w", line, err)
+		}
+		expire := c.defaultExpire(entry.Key, entry.Value)
+		if entry.TTL != "" {
+			ttl, err := time.ParseDuration(entry.TTL)
+			if err != nil {
+				return fmt.Errorf("cachex: warmup entry %d: %w", line, err)
+			}
+			expire = ttl
+		}
+		s := c.shard(entry.Key)
+		s.lock()
+		s.setItem(entry.Key, c.newItem(entry.Key, entry.Value, c.jitter(expire)))
+		s.unlock()
+	}
+}
+
+func (c *BaseCache[K, V]) warmupFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.WarmFrom(f)
+}
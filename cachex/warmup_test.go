@@ -0,0 +1,95 @@
+package cachex
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarmFromJSONLines(t *testing.T) {
+	cache := NewBaseCache(OnceCacheOption[string, int]{DefaultKeyExpire: time.Hour})
+	defer cache.Close()
+	input := `
+{"key": "a", "value": 1}
+{"key": "b", "value": 2, "ttl": "1m"}
+`
+	if err := cache.WarmFrom(strings.NewReader(input)); err != nil {
+		t.Fatalf("WarmFrom failed: %v", err)
+	}
+	if v, ttl, ok := cache.GetWithTTL("a"); !ok || v != 1 || ttl <= time.Minute {
+		t.Errorf("Expected a with DefaultKeyExpire, got %v, %v, ok: %v", v, ttl, ok)
+	}
+	if v, ttl, ok := cache.GetWithTTL("b"); !ok || v != 2 || ttl > time.Minute {
+		t.Errorf("Expected b with its own ttl, got %v, %v, ok: %v", v, ttl, ok)
+	}
+	if err := cache.WarmFrom(strings.NewReader(`{"key": "c", "value": "x"}`)); err == nil {
+		t.Errorf("Expected error for a value of the wrong type")
+	}
+	if err := cache.WarmFrom(strings.NewReader("")); err != nil {
+		t.Errorf("Expected empty input to be accepted, got %v", err)
+	}
+}
+
+func TestWarmupFile(t *testing.T) {
+	src := NewBaseCache(OnceCacheOption[string, int]{})
+	src.Set("a", 1)
+	src.SetExpire("b", 2, time.Minute)
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	src.Close()
+	name := filepath.Join(t.TempDir(), "cache.gob")
+	if err := os.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewBaseCache(OnceCacheOption[string, int]{WarmupFile: name})
+	defer cache.Close()
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected a from snapshot, got %v, ok: %v", v, ok)
+	}
+	if _, ttl, ok := cache.GetWithTTL("b"); !ok || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Expected b to keep its expiry, got %v, ok: %v", ttl, ok)
+	}
+
+	var warmErr error
+	missing := NewBaseCache(OnceCacheOption[string, int]{
+		WarmupFile:    filepath.Join(t.TempDir(), "missing"),
+		OnWarmupError: func(err error) { warmErr = err },
+	})
+	defer missing.Close()
+	if !os.IsNotExist(warmErr) {
+		t.Errorf("Expected not-exist error, got %v", warmErr)
+	}
+}
+
+func TestWarmFromSnapshotMagic(t *testing.T) {
+	src := NewBaseCache(OnceCacheOption[string, int]{})
+	src.Set("a", 1)
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	src.Close()
+	if !strings.HasPrefix(buf.String(), snapshotMagic) {
+		t.Fatalf("Expected snapshot to start with its magic prefix")
+	}
+
+	cache := NewBaseCache(OnceCacheOption[string, int]{})
+	defer cache.Close()
+	if err := cache.WarmFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("WarmFrom failed: %v", err)
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected a from snapshot, got %v, ok: %v", v, ok)
+	}
+
+	// 没有标识的 gob 数据不会被当作快照
+	if _, err := NewBaseCacheFromSnapshot(bytes.NewReader(buf.Bytes()[len(snapshotMagic):]), OnceCacheOption[string, int]{}); err == nil {
+		t.Errorf("Expected data without the magic prefix to be rejected")
+	}
+}
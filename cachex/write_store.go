@@ -0,0 +1,125 @@
+package cachex
+
+import (
+	"context"
+	"time"
+)
+
+// WriteMode 写入持久化存储的方式
+type WriteMode int
+
+const (
+	// WriteThrough 在 Set 和 Del 中同步写入存储，写入失败时缓存保持不变
+	WriteThrough WriteMode = iota
+	// WriteBehind 先更新缓存，再由后台协程按顺序写入存储，缓存关闭时会写完队列中剩余的数据
+	WriteBehind
+)
+
+func (m WriteMode) String() string {
+	switch m {
+	case WriteThrough:
+		return "write-through"
+	case WriteBehind:
+		return "write-behind"
+	}
+	return "unknown"
+}
+
+type writeOp[K comparable] struct {
+	key  K
+	data []byte
+	ttl  time.Duration
+	del  bool
+}
+
+// storeWriter 负责把缓存的写入同步到 Store
+// 写入在分片锁内发起，同一个键的写入顺序与缓存中的顺序一致，每次写入受 StoreTimeout 限制
+type storeWriter[K comparable, V any] struct {
+	c       *BaseCache[K, V]
+	codec   Codec
+	timeout time.Duration
+	queue   chan writeOp[K]
+	done    chan struct{}
+}
+
+func newStoreWriter[K comparable, V any](c *BaseCache[K, V]) *storeWriter[K, V] {
+	w := &storeWriter[K, V]{c: c, codec: c.opts.Codec, timeout: c.opts.StoreTimeout}
+	if w.codec == nil {
+		w.codec = JSONCodec{}
+	}
+	if w.timeout <= 0 {
+		w.timeout = 5 * time.Second
+	}
+	if c.opts.WriteMode == WriteBehind {
+		n := c.opts.WriteQueue
+		if n <= 0 {
+			n = 1024
+		}
+		w.queue = make(chan writeOp[K], n)
+		w.done = make(chan struct{})
+		go w.run()
+	}
+	return w
+}
+
+// set 写入一个值，返回 false 时缓存不应更新
+func (w *storeWriter[K, V]) set(key K, value V, ttl time.Duration) bool {
+	data, err := w.codec.Marshal(value)
+	if err != nil {
+		w.onError(key, err)
+		return false
+	}
+	return w.write(writeOp[K]{key: key, data: data, ttl: ttl})
+}
+
+// del 删除一个键，返回 false 时缓存不应更新
+func (w *storeWriter[K, V]) del(key K) bool {
+	return w.write(writeOp[K]{key: key, del: true})
+}
+
+func (w *storeWriter[K, V]) write(op writeOp[K]) bool {
+	if w.queue != nil {
+		w.queue <- op
+		return true
+	}
+	if err := w.apply(op); err != nil {
+		w.onError(op.key, err)
+		return false
+	}
+	return true
+}
+
+func (w *storeWriter[K, V]) run() {
+	defer close(w.done)
+	for op := range w.queue {
+		if err := w.apply(op); err != nil {
+			w.onError(op.key, err)
+		}
+	}
+}
+
+// flush 关闭写入队列并等待剩余的写入完成，调用后不能再写入
+func (w *storeWriter[K, V]) flush() {
+	if w.queue == nil {
+		return
+	}
+	close(w.queue)
+	<-w.done
+}
+
+func (w *storeWriter[K, V]) apply(op writeOp[K]) error {
+	// 缓存关闭时仍需写完队列，不使用缓存自身的 ctx
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	key := w.c.keyString(op.key)
+	if op.del {
+		return w.c.opts.Store.Del(ctx, key)
+	}
+	return w.c.opts.Store.Set(ctx, key, op.data, op.ttl)
+}
+
+func (w *storeWriter[K, V]) onError(key K, err error) {
+	if w.c.opts.OnStoreError != nil {
+		w.c.opts.OnStoreError(key, err)
+	}
+}
@@ -0,0 +1,123 @@
+package cachex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingStore 写入总是失败的存储
+type failingStore struct {
+	*MemoryStore
+}
+
+func (s failingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func TestWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	cache := NewBaseCache(OnceCacheOption[int, user]{Store: store})
+	defer cache.Close()
+
+	cache.Set(1, user{Name: "alice", Age: 20})
+	data, ok, _ := store.Get(ctx, "1")
+	if !ok || string(data) != `{"Name":"alice","Age":20}` {
+		t.Errorf("Expected value to be written through, got %s, ok: %v", data, ok)
+	}
+	cache.SetExpire(2, user{Name: "bob"}, time.Minute)
+	if ttl, ok, _ := store.TTL(ctx, "2"); !ok || ttl <= 0 {
+		t.Errorf("Expected store ttl to follow key expire, got %v, ok: %v", ttl, ok)
+	}
+	cache.Del(1)
+	if _, ok, _ := store.Get(ctx, "1"); ok {
+		t.Errorf("Expected key to be deleted from store")
+	}
+}
+
+func TestWriteThroughError(t *testing.T) {
+	var failed []string
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Store: failingStore{NewMemoryStore()},
+		OnStoreError: func(key string, err error) {
+			failed = append(failed, key)
+		},
+	})
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected cache to stay unchanged when write-through fails")
+	}
+	if len(failed) != 1 || failed[0] != "a" {
+		t.Errorf("Expected OnStoreError for 'a', got %v", failed)
+	}
+}
+
+func TestWriteBehind(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Store:      store,
+		WriteMode:  WriteBehind,
+		WriteQueue: 4,
+		StoreKey: func(key string) string {
+			return "k:" + key
+		},
+	})
+
+	for i := 0; i < 100; i++ {
+		cache.Set("a", i)
+	}
+	cache.Set("b", 1)
+	cache.Del("b")
+	if v, ok := cache.Get("a"); !ok || v != 99 {
+		t.Errorf("Expected cache to be updated immediately, got %v, ok: %v", v, ok)
+	}
+
+	// 关闭时写完队列中剩余的数据
+	cache.Close()
+	if data, ok, _ := store.Get(ctx, "k:a"); !ok || string(data) != "99" {
+		t.Errorf("Expected last write to be flushed, got %s, ok: %v", data, ok)
+	}
+	if _, ok, _ := store.Get(ctx, "k:b"); ok {
+		t.Errorf("Expected 'b' to be deleted from store")
+	}
+	cache.Set("c", 1)
+	if _, ok, _ := store.Get(ctx, "k:c"); ok {
+		t.Errorf("Expected closed cache not to write")
+	}
+}
+
+// slowStore 写入一直阻塞到 ctx 结束
+type slowStore struct {
+	*MemoryStore
+}
+
+func (s slowStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWriteThroughTimeout(t *testing.T) {
+	var errs []error
+	cache := NewBaseCache(OnceCacheOption[string, int]{
+		Store:        slowStore{NewMemoryStore()},
+		StoreTimeout: 10 * time.Millisecond,
+		OnStoreError: func(key string, err error) {
+			errs = append(errs, err)
+		},
+	})
+	defer cache.Close()
+
+	// 卡住的 Store 在超时后按写入失败处理，不会一直占用分片锁
+	cache.Set("a", 1)
+	if len(errs) != 1 || !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("Expected write to time out, got %v", errs)
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Errorf("Expected cache to stay unchanged when the write times out")
+	}
+}
@@ -11,7 +11,7 @@ var compareHolder *syncx.Holder[*cachex.BaseCache[string, time.Time]]
 
 func init() {
 	compareHolder = syncx.NewHolder[*cachex.BaseCache[string, time.Time]](func() *cachex.BaseCache[string, time.Time] {
-		return cachex.NewBaseCache[string, time.Time](cachex.OnceCacheOption{
+		return cachex.NewBaseCache(cachex.OnceCacheOption[string, time.Time]{
 			Expire:           30 * time.Second,
 			CheckInterval:    0,
 			DefaultKeyExpire: 0,
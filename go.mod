@@ -1,5 +1,27 @@
 module github.com/llyb120/gotool
 
-require github.com/petermattis/goid v0.0.0-20250303134427-723919f7f203 // indirect
+require (
+	github.com/alicebob/miniredis/v2 v2.33.0
+	github.com/petermattis/goid v0.0.0-20250303134427-723919f7f203
+	github.com/prometheus/client_golang v1.16.0
+	github.com/redis/go-redis/v9 v9.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
-go 1.18
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.4 // indirect
+	github.com/prometheus/client_model v0.3.0 // indirect
+	github.com/prometheus/common v0.42.0 // indirect
+	github.com/prometheus/procfs v0.10.1 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/sys v0.8.0 // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+)
+
+go 1.23
@@ -0,0 +1,179 @@
+package stlx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BiMap 是一个协程安全的双向映射，键和值都是唯一的，可以按键查值也可以按值查键
+// 零值可以直接使用，数据在第一次访问时初始化
+type BiMap[K comparable, V comparable] struct {
+	state atomic.Pointer[biMapState[K, V]]
+}
+
+// biMapState 双向映射的数据和锁，Inverse 得到的视图与原映射共享同一份，只是方向相反
+type biMapState[K comparable, V comparable] struct {
+	mu       *sync.RWMutex
+	forward  map[K]V
+	backward map[V]K
+}
+
+// NewBiMap 创建一个新的双向映射
+func NewBiMap[K comparable, V comparable]() *BiMap[K, V] {
+	bm := &BiMap[K, V]{}
+	bm.load()
+	return bm
+}
+
+// Set 写入键值对，键或值已经属于其他键值对时，原有的键值对会被删除
+func (bm *BiMap[K, V]) Set(key K, value V) {
+	st := bm.load()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.set(key, value)
+}
+
+// SetIfAbsent 键和值都不存在时写入并返回 true，否则不做修改并返回 false
+func (bm *BiMap[K, V]) SetIfAbsent(key K, value V) bool {
+	st := bm.load()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.forward[key]; ok {
+		return false
+	}
+	if _, ok := st.backward[value]; ok {
+		return false
+	}
+	st.set(key, value)
+	return true
+}
+
+// Get 获取键对应的值
+func (bm *BiMap[K, V]) Get(key K) (V, bool) {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	value, ok := st.forward[key]
+	return value, ok
+}
+
+// GetByValue 获取值对应的键
+func (bm *BiMap[K, V]) GetByValue(value V) (K, bool) {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	key, ok := st.backward[value]
+	return key, ok
+}
+
+// Del 删除键及其对应的值，并返回被删除的值
+func (bm *BiMap[K, V]) Del(key K) V {
+	st := bm.load()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	value, ok := st.forward[key]
+	if ok {
+		delete(st.forward, key)
+		delete(st.backward, value)
+	}
+	return value
+}
+
+// DelByValue 删除值及其对应的键，并返回被删除的键
+func (bm *BiMap[K, V]) DelByValue(value V) K {
+	return bm.Inverse().Del(value)
+}
+
+// Inverse 返回值到键方向的视图，视图与原映射共享数据和锁，对任一方的修改另一方立即可见
+func (bm *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	st := bm.load()
+	inverse := &BiMap[V, K]{}
+	inverse.state.Store(&biMapState[V, K]{mu: st.mu, forward: st.backward, backward: st.forward})
+	return inverse
+}
+
+// Len 返回键值对的数量
+func (bm *BiMap[K, V]) Len() int {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return len(st.forward)
+}
+
+// Keys 返回所有的键，顺序不固定
+func (bm *BiMap[K, V]) Keys() []K {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	keys := make([]K, 0, len(st.forward))
+	for key := range st.forward {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Vals 返回所有的值，顺序不固定
+func (bm *BiMap[K, V]) Vals() []V {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	values := make([]V, 0, len(st.backward))
+	for value := range st.backward {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Clear 清空映射，通过 Inverse 得到的视图同样被清空
+func (bm *BiMap[K, V]) Clear() {
+	st := bm.load()
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	clear(st.forward)
+	clear(st.backward)
+}
+
+// For 遍历所有键值对，顺序不固定
+func (bm *BiMap[K, V]) For(fn func(key K, value V) bool) {
+	st := bm.load()
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	for key, value := range st.forward {
+		if !fn(key, value) {
+			break
+		}
+	}
+}
+
+func (st *biMapState[K, V]) set(key K, value V) {
+	if old, ok := st.forward[key]; ok {
+		delete(st.backward, old)
+	}
+	if old, ok := st.backward[value]; ok {
+		delete(st.forward, old)
+	}
+	st.forward[key] = value
+	st.backward[value] = key
+}
+
+// load 返回映射的数据，零值的映射在第一次访问时初始化
+func (bm *BiMap[K, V]) load() *biMapState[K, V] {
+	if st := bm.state.Load(); st != nil {
+		return st
+	}
+	bm.state.CompareAndSwap(nil, &biMapState[K, V]{
+		mu:       new(sync.RWMutex),
+		forward:  make(map[K]V),
+		backward: make(map[V]K),
+	})
+	return bm.state.Load()
+}
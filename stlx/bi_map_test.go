@@ -0,0 +1,73 @@
+package stlx
+
+import "testing"
+
+func TestBiMap(t *testing.T) {
+	var _ Map[int, string] = NewBiMap[int, string]()
+	bm := NewBiMap[int, string]()
+	bm.Set(1, "alice")
+	bm.Set(2, "bob")
+
+	if name, _ := bm.Get(1); name != "alice" {
+		t.Errorf("Expected alice, got %s", name)
+	}
+	if id, ok := bm.GetByValue("bob"); !ok || id != 2 {
+		t.Errorf("Expected 2, got %d", id)
+	}
+
+	// 值已经属于其他键时，原有的键值对被删除
+	bm.Set(3, "alice")
+	if _, ok := bm.Get(1); ok || bm.Len() != 2 {
+		t.Errorf("Expected 1 to be replaced, len %d", bm.Len())
+	}
+	// 键已存在时，旧的值不再能查到
+	bm.Set(2, "carol")
+	if _, ok := bm.GetByValue("bob"); ok {
+		t.Errorf("Expected bob to be removed")
+	}
+	if bm.SetIfAbsent(4, "carol") || bm.SetIfAbsent(2, "dave") || !bm.SetIfAbsent(4, "dave") {
+		t.Errorf("Unexpected SetIfAbsent results")
+	}
+
+	inv := bm.Inverse()
+	if id, _ := inv.Get("dave"); id != 4 {
+		t.Errorf("Expected inverse view to see 4, got %d", id)
+	}
+	inv.Set("erin", 5)
+	if name, _ := bm.Get(5); name != "erin" {
+		t.Errorf("Expected write through inverse view, got %s", name)
+	}
+	if id := bm.DelByValue("carol"); id != 2 || bm.Len() != 3 {
+		t.Errorf("Expected carol to be deleted, got %d, len %d", id, bm.Len())
+	}
+	if name := bm.Del(3); name != "alice" || inv.Len() != 2 {
+		t.Errorf("Expected alice to be deleted, got %s", name)
+	}
+	bm.Clear()
+	if inv.Len() != 0 {
+		t.Errorf("Expected inverse view to be cleared")
+	}
+}
+
+func TestBiMapZeroValue(t *testing.T) {
+	var bm BiMap[int, string]
+	if bm.Len() != 0 {
+		t.Errorf("Expected empty map, got %d", bm.Len())
+	}
+	bm.Set(1, "alice")
+
+	// 零值初始化后 Inverse 仍与原映射共享数据
+	inv := bm.Inverse()
+	if id, ok := inv.Get("alice"); !ok || id != 1 {
+		t.Errorf("Expected 1, got %d, ok: %v", id, ok)
+	}
+	inv.Set("bob", 2)
+	if name, ok := bm.Get(2); !ok || name != "bob" {
+		t.Errorf("Expected bob, got %s, ok: %v", name, ok)
+	}
+
+	var other BiMap[int, string]
+	if inv := other.Inverse(); !inv.SetIfAbsent("carol", 3) || other.Len() != 1 {
+		t.Errorf("Expected inverse of zero value to share data, len %d", other.Len())
+	}
+}
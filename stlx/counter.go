@@ -0,0 +1,144 @@
+package stlx
+
+import (
+	"sort"
+	"sync"
+)
+
+// Counter 是一个协程安全的计数器，记录每个元素出现的次数，可以当作多重集合使用
+// 计数小于等于0的元素会被删除，零值可以直接使用
+type Counter[T comparable] struct {
+	mu     sync.RWMutex
+	counts map[T]int
+	total  int
+}
+
+// NewCounter 创建一个计数器，并对给定的元素各计数一次
+func NewCounter[T comparable](items ...T) *Counter[T] {
+	c := &Counter[T]{counts: make(map[T]int)}
+	for _, item := range items {
+		c.add(item, 1)
+	}
+	return c
+}
+
+// Add 把元素的计数增加 n，n 可以为负数，返回增加后的计数
+func (c *Counter[T]) Add(item T, n int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.add(item, n)
+}
+
+// Count 返回元素的计数，不存在时为0
+func (c *Counter[T]) Count(item T) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.counts[item]
+}
+
+// Total 返回所有元素的计数之和
+func (c *Counter[T]) Total() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.total
+}
+
+// Len 返回不同元素的数量
+func (c *Counter[T]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.counts)
+}
+
+// Del 删除元素，并返回它原来的计数
+func (c *Counter[T]) Del(item T) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.counts[item]
+	delete(c.counts, item)
+	c.total -= n
+	return n
+}
+
+// Clear 清空计数器
+func (c *Counter[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts = make(map[T]int)
+	c.total = 0
+}
+
+// MostCommon 按计数从大到小返回前 k 个元素及其计数，k 小于等于0或超过元素数量时返回全部，计数相同的元素顺序不固定
+func (c *Counter[T]) MostCommon(k int) []Pair[T, int] {
+	c.mu.RLock()
+	pairs := make([]Pair[T, int], 0, len(c.counts))
+	for item, n := range c.counts {
+		pairs = append(pairs, Pair[T, int]{Key: item, Value: n})
+	}
+	c.mu.RUnlock()
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Value > pairs[j].Value })
+	if k > 0 && k < len(pairs) {
+		pairs = pairs[:k]
+	}
+	return pairs
+}
+
+// Merge 把 other 中每个元素的计数加到计数器上
+// other 为计数器自身时每个计数翻倍
+func (c *Counter[T]) Merge(other *Counter[T]) {
+	counts := other.snapshot()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for item, n := range counts {
+		c.add(item, n)
+	}
+}
+
+// For 遍历所有元素及其计数，顺序不固定
+func (c *Counter[T]) For(fn func(item T, count int) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for item, n := range c.counts {
+		if !fn(item, n) {
+			break
+		}
+	}
+}
+
+// add 需在持有写锁的情况下调用，计数变为小于等于0时删除元素
+func (c *Counter[T]) add(item T, n int) int {
+	old := c.counts[item]
+	count := old + n
+	if count <= 0 {
+		delete(c.counts, item)
+		c.total -= old
+		return 0
+	}
+	if c.counts == nil {
+		c.counts = make(map[T]int)
+	}
+	c.counts[item] = count
+	c.total += n
+	return count
+}
+
+func (c *Counter[T]) snapshot() map[T]int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	counts := make(map[T]int, len(c.counts))
+	for item, n := range c.counts {
+		counts[item] = n
+	}
+	return counts
+}
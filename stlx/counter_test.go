@@ -0,0 +1,61 @@
+package stlx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	words := NewCounter(strings.Fields("the cat and the dog and the bird")...)
+	if n := words.Count("the"); n != 3 {
+		t.Errorf("Expected 3, got %d", n)
+	}
+	if words.Total() != 8 || words.Len() != 5 {
+		t.Errorf("Expected total 8 over 5 words, got %d over %d", words.Total(), words.Len())
+	}
+	top := words.MostCommon(2)
+	if len(top) != 2 || top[0] != (Pair[string, int]{Key: "the", Value: 3}) || top[1].Key != "and" {
+		t.Errorf("Unexpected most common %v", top)
+	}
+	if all := words.MostCommon(0); len(all) != 5 {
+		t.Errorf("Expected all entries, got %v", all)
+	}
+
+	if n := words.Add("cat", 4); n != 5 {
+		t.Errorf("Expected 5, got %d", n)
+	}
+	if n := words.Add("dog", -3); n != 0 || words.Len() != 4 {
+		t.Errorf("Expected dog to be removed, got %d", n)
+	}
+	if words.Total() != 11 {
+		t.Errorf("Expected total 11, got %d", words.Total())
+	}
+
+	other := NewCounter("cat", "fish")
+	words.Merge(other)
+	words.Merge(words)
+	if words.Count("cat") != 12 || words.Count("fish") != 2 || words.Total() != 26 {
+		t.Errorf("Unexpected counts after merge: cat %d fish %d total %d", words.Count("cat"), words.Count("fish"), words.Total())
+	}
+	if n := words.Del("cat"); n != 12 || words.Total() != 14 {
+		t.Errorf("Expected to delete 12, got %d, total %d", n, words.Total())
+	}
+	words.Clear()
+	if words.Total() != 0 || words.Len() != 0 {
+		t.Errorf("Expected empty counter")
+	}
+}
+
+func TestCounterZeroValue(t *testing.T) {
+	var c Counter[string]
+	if c.Add("a", -1) != 0 || c.Len() != 0 {
+		t.Errorf("Expected negative add on empty counter to be dropped")
+	}
+	c.Add("a", 2)
+	var merged Counter[string]
+	merged.Merge(&c)
+	merged.Merge(&Counter[string]{})
+	if merged.Count("a") != 2 || merged.Total() != 2 {
+		t.Errorf("Unexpected merge result: a %d total %d", merged.Count("a"), merged.Total())
+	}
+}
@@ -0,0 +1,153 @@
+package stlx
+
+import "sync"
+
+// DefaultMap 是一个协程安全的映射，读取不存在的键时用工厂函数创建值并写入，类似 Python 的 defaultdict
+// 零值可以直接使用，此时没有工厂函数，不存在的键以 V 的零值作为默认值
+type DefaultMap[K comparable, V any] struct {
+	mu      sync.RWMutex
+	data    map[K]V
+	factory func(key K) V
+}
+
+// NewDefaultMap 创建一个新的映射，factory 为键创建默认值，为 nil 时返回 nil
+func NewDefaultMap[K comparable, V any](factory func(key K) V) *DefaultMap[K, V] {
+	if factory == nil {
+		return nil
+	}
+	return &DefaultMap[K, V]{
+		data:    make(map[K]V),
+		factory: factory,
+	}
+}
+
+// Get 获取键对应的值，键不存在时创建默认值并写入
+// 同一个键的默认值只会创建一次，factory 在写锁内执行，不能再访问该映射
+func (dm *DefaultMap[K, V]) Get(key K) V {
+	dm.mu.RLock()
+	value, ok := dm.data[key]
+	dm.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	return dm.get(key)
+}
+
+// Lookup 获取键对应的值，键不存在时不创建默认值
+func (dm *DefaultMap[K, V]) Lookup(key K) (V, bool) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	value, ok := dm.data[key]
+	return value, ok
+}
+
+// Update 在写锁内用 fn 的返回值替换键对应的值，键不存在时传入默认值，返回新的值
+// 值是切片等需要重新赋值的类型时，用 Update 代替 Get 后再 Set，避免并发修改时丢失更新
+func (dm *DefaultMap[K, V]) Update(key K, fn func(value V) V) V {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	value := fn(dm.get(key))
+	dm.set(key, value)
+	return value
+}
+
+// Set 添加或更新键值对
+func (dm *DefaultMap[K, V]) Set(key K, value V) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.set(key, value)
+}
+
+// Has 判断键是否存在，不会创建默认值
+func (dm *DefaultMap[K, V]) Has(key K) bool {
+	_, ok := dm.Lookup(key)
+	return ok
+}
+
+// Del 删除键值对，并返回被删除的值
+func (dm *DefaultMap[K, V]) Del(key K) V {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	value := dm.data[key]
+	delete(dm.data, key)
+	return value
+}
+
+// Len 返回映射大小
+func (dm *DefaultMap[K, V]) Len() int {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return len(dm.data)
+}
+
+// Keys 返回所有的键，顺序不固定
+func (dm *DefaultMap[K, V]) Keys() []K {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	keys := make([]K, 0, len(dm.data))
+	for key := range dm.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Vals 返回所有的值，顺序不固定
+func (dm *DefaultMap[K, V]) Vals() []V {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	values := make([]V, 0, len(dm.data))
+	for _, value := range dm.data {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Clear 清空映射
+func (dm *DefaultMap[K, V]) Clear() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	dm.data = make(map[K]V)
+}
+
+// For 遍历所有键值对，顺序不固定
+func (dm *DefaultMap[K, V]) For(fn func(key K, value V) bool) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	for key, value := range dm.data {
+		if !fn(key, value) {
+			break
+		}
+	}
+}
+
+// 以下方法均需在持有写锁的情况下调用
+
+func (dm *DefaultMap[K, V]) get(key K) V {
+	value, ok := dm.data[key]
+	if !ok {
+		if dm.factory != nil {
+			value = dm.factory(key)
+		}
+		dm.set(key, value)
+	}
+	return value
+}
+
+func (dm *DefaultMap[K, V]) set(key K, value V) {
+	if dm.data == nil {
+		dm.data = make(map[K]V)
+	}
+	dm.data[key] = value
+}
@@ -0,0 +1,59 @@
+package stlx
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestDefaultMap(t *testing.T) {
+	if NewDefaultMap[string, int](nil) != nil {
+		t.Errorf("Expected nil map without factory")
+	}
+	calls := 0
+	groups := NewDefaultMap(func(key string) *OrderedMap[string, int] {
+		calls++
+		return NewMap[string, int]()
+	})
+	groups.Get("a").Set("x", 1)
+	groups.Get("a").Set("y", 2)
+	if calls != 1 || groups.Get("a").Len() != 2 {
+		t.Errorf("Expected factory to run once, got %d calls", calls)
+	}
+	if _, ok := groups.Lookup("b"); ok || groups.Has("b") || groups.Len() != 1 {
+		t.Errorf("Expected Lookup and Has not to create entries")
+	}
+
+	lists := NewDefaultMap(func(key string) []int { return nil })
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lists.Update("k", func(v []int) []int { return append(v, i) })
+		}(i)
+	}
+	wg.Wait()
+	if n := len(lists.Get("k")); n != 50 {
+		t.Errorf("Expected 50 appended values, got %d", n)
+	}
+	lists.Del("k")
+	if lists.Len() != 0 {
+		t.Errorf("Expected empty map after Del")
+	}
+	if v := lists.Get("new"); v != nil || lists.Len() != 1 {
+		t.Errorf("Expected default value to be stored")
+	}
+}
+
+func TestDefaultMapZeroValue(t *testing.T) {
+	var dm DefaultMap[string, int]
+	if v := dm.Get("a"); v != 0 || !dm.Has("a") {
+		t.Errorf("Expected zero default to be stored, got %d", v)
+	}
+	dm.Update("b", func(v int) int { return v + 1 })
+	var other DefaultMap[string, int]
+	other.Set("c", 3)
+	if dm.Len() != 2 || other.Len() != 1 {
+		t.Errorf("Unexpected lengths %d, %d", dm.Len(), other.Len())
+	}
+}
@@ -0,0 +1,175 @@
+package stlx
+
+import "sync"
+
+// HashSet 是一个协程安全的无序集合，基于 map 实现
+// 集合运算都返回新的集合，不修改参与运算的集合，参与运算的另一个集合也可以是自身
+type HashSet[T comparable] struct {
+	mu    sync.RWMutex
+	items map[T]void
+}
+
+// NewHashSet 创建一个包含给定元素的集合
+func NewHashSet[T comparable](elements ...T) *HashSet[T] {
+	hs := &HashSet[T]{items: make(map[T]void, len(elements))}
+	for _, element := range elements {
+		hs.items[element] = void{}
+	}
+	return hs
+}
+
+// Add 添加元素到集合
+func (hs *HashSet[T]) Add(element T) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.add(element)
+}
+
+// Del 从集合中移除元素
+func (hs *HashSet[T]) Del(element T) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	delete(hs.items, element)
+}
+
+// Has 检查元素是否在集合中
+func (hs *HashSet[T]) Has(element T) bool {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	_, ok := hs.items[element]
+	return ok
+}
+
+// Len 返回集合大小
+func (hs *HashSet[T]) Len() int {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+	return len(hs.items)
+}
+
+// Clear 清空集合
+func (hs *HashSet[T]) Clear() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.clear()
+}
+
+// Vals 返回所有元素的切片，顺序不固定
+func (hs *HashSet[T]) Vals() []T {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return hs.vals()
+}
+
+// For 遍历集合中的所有元素，顺序不固定
+func (hs *HashSet[T]) For(fn func(element T) bool) {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	hs.foreach(fn)
+}
+
+// Union 返回两个集合的并集
+func (hs *HashSet[T]) Union(other *HashSet[T]) *HashSet[T] {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	result := &HashSet[T]{items: make(map[T]void, len(hs.items)+len(others))}
+	for element := range hs.items {
+		result.items[element] = void{}
+	}
+	for element := range others {
+		result.items[element] = void{}
+	}
+	return result
+}
+
+// Intersect 返回两个集合的交集
+func (hs *HashSet[T]) Intersect(other *HashSet[T]) *HashSet[T] {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	result := NewHashSet[T]()
+	for element := range hs.items {
+		if _, ok := others[element]; ok {
+			result.items[element] = void{}
+		}
+	}
+	return result
+}
+
+// Difference 返回在集合中但不在 other 中的元素
+func (hs *HashSet[T]) Difference(other *HashSet[T]) *HashSet[T] {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	result := NewHashSet[T]()
+	for element := range hs.items {
+		if _, ok := others[element]; !ok {
+			result.items[element] = void{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference 返回只在其中一个集合中的元素
+func (hs *HashSet[T]) SymmetricDifference(other *HashSet[T]) *HashSet[T] {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	result := NewHashSet[T]()
+	for element := range hs.items {
+		if _, ok := others[element]; !ok {
+			result.items[element] = void{}
+		}
+	}
+	for element := range others {
+		if _, ok := hs.items[element]; !ok {
+			result.items[element] = void{}
+		}
+	}
+	return result
+}
+
+// IsSubset 判断集合的所有元素是否都在 other 中
+func (hs *HashSet[T]) IsSubset(other *HashSet[T]) bool {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return containsAll(others, hs.items)
+}
+
+// IsSuperset 判断 other 的所有元素是否都在集合中
+func (hs *HashSet[T]) IsSuperset(other *HashSet[T]) bool {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return containsAll(hs.items, others)
+}
+
+// Equal 判断两个集合是否包含相同的元素
+func (hs *HashSet[T]) Equal(other *HashSet[T]) bool {
+	others := other.snapshot()
+
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	return len(hs.items) == len(others) && containsAll(hs.items, others)
+}
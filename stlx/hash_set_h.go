@@ -0,0 +1,79 @@
+package stlx
+
+func (hs *HashSet[T]) add(element T) {
+	if hs.items == nil {
+		hs.items = make(map[T]void)
+	}
+	hs.items[element] = void{}
+}
+
+func (hs *HashSet[T]) clear() {
+	hs.items = make(map[T]void)
+}
+
+func (hs *HashSet[T]) vals() []T {
+	elements := make([]T, 0, len(hs.items))
+	for element := range hs.items {
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+func (hs *HashSet[T]) foreach(fn func(element T) bool) {
+	for element := range hs.items {
+		if !fn(element) {
+			break
+		}
+	}
+}
+
+// snapshot 在读锁下复制集合的元素，用于与另一个集合运算
+func (hs *HashSet[T]) snapshot() map[T]void {
+	hs.mu.RLock()
+	defer hs.mu.RUnlock()
+
+	items := make(map[T]void, len(hs.items))
+	for element := range hs.items {
+		items[element] = void{}
+	}
+	return items
+}
+
+// containsAll 判断 sub 的所有元素是否都在 super 中
+func containsAll[T comparable](super, sub map[T]void) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	for element := range sub {
+		if _, ok := super[element]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (hs *HashSet[T]) lock() {
+	hs.mu.Lock()
+}
+
+func (hs *HashSet[T]) unlock() {
+	hs.mu.Unlock()
+}
+
+func (hs *HashSet[T]) rlock() {
+	hs.mu.RLock()
+}
+
+func (hs *HashSet[T]) runlock() {
+	hs.mu.RUnlock()
+}
+
+// MarshalJSON 实现json.Marshaler接口，输出为数组，顺序不固定
+func (hs *HashSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection[T](hs)
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口，原有内容会被清空
+func (hs *HashSet[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalCollection[T](hs, data)
+}
@@ -0,0 +1,72 @@
+package stlx
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+)
+
+func sortedVals(hs *HashSet[int]) []int {
+	vals := hs.Vals()
+	sort.Ints(vals)
+	return vals
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHashSet(t *testing.T) {
+	var _ Set[int] = NewHashSet[int]()
+	a := NewHashSet(1, 2, 3, 3)
+	b := NewHashSet(3, 4)
+	if a.Len() != 3 || !a.Has(2) || a.Has(4) {
+		t.Errorf("Unexpected contents %v", a.Vals())
+	}
+
+	cases := []struct {
+		name string
+		got  *HashSet[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4}},
+		{"Intersect", a.Intersect(b), []int{3}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 4}},
+		{"Self", a.Intersect(a), []int{1, 2, 3}},
+	}
+	for _, c := range cases {
+		if got := sortedVals(c.got); !equalInts(got, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+	if a.Len() != 3 || b.Len() != 2 {
+		t.Errorf("Expected operands to be unchanged")
+	}
+
+	sub := NewHashSet(1, 3)
+	if !sub.IsSubset(a) || sub.IsSuperset(a) || !a.IsSuperset(sub) || b.IsSubset(a) {
+		t.Errorf("Unexpected subset relations")
+	}
+	if !a.Equal(NewHashSet(3, 2, 1)) || a.Equal(sub) || !NewHashSet[int]().Equal(NewHashSet[int]()) {
+		t.Errorf("Unexpected equality results")
+	}
+
+	a.Del(1)
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	var c HashSet[int]
+	if err := json.Unmarshal(data, &c); err != nil || !c.Equal(a) {
+		t.Errorf("Expected JSON round-trip, got %v, err: %v", c.Vals(), err)
+	}
+}
@@ -2,13 +2,17 @@ package stlx
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 )
 
+// marshalMap 按遍历顺序把映射序列化为 JSON 对象，键的编码规则与 encoding/json 对 map 键的处理一致
 func marshalMap[K any, V any](mp jsonMap[K, V]) ([]byte, error) {
-	mp.lock()
-	defer mp.unlock()
+	mp.rlock()
+	defer mp.runlock()
 
 	var buf bytes.Buffer
 	buf.WriteByte('{')
@@ -21,12 +25,13 @@ func marshalMap[K any, V any](mp jsonMap[K, V]) ([]byte, error) {
 		}
 		first = false
 
-		// 序列化键
-		keyBytes, err := json.Marshal(key)
+		// 序列化键，JSON 对象的键只能是字符串
+		keyStr, err := encodeKey(key)
 		if err != nil {
 			reultErr = err
 			return false
 		}
+		keyBytes, _ := json.Marshal(keyStr)
 		buf.Write(keyBytes)
 
 		buf.WriteByte(':')
@@ -53,6 +58,11 @@ func unmarshalMap[K any, V any](mp jsonMap[K, V], data []byte) error {
 	// 清空现有数据
 	mp.clear()
 
+	// null 视为空映射
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+
 	dec := json.NewDecoder(bytes.NewReader(data))
 
 	// 确保开始是一个对象
@@ -70,16 +80,12 @@ func unmarshalMap[K any, V any](mp jsonMap[K, V], data []byte) error {
 		if err != nil {
 			return err
 		}
-
-		// 如果键是字符串类型，需要特殊处理
-		if keyStr, ok := keyToken.(string); ok {
-			if err := json.Unmarshal([]byte(`"`+keyStr+`"`), &key); err != nil {
-				return err
-			}
-		} else {
-			if err := json.Unmarshal([]byte(fmt.Sprintf("%v", keyToken)), &key); err != nil {
-				return err
-			}
+		keyStr, ok := keyToken.(string)
+		if !ok {
+			return fmt.Errorf("expected object key, got %v", keyToken)
+		}
+		if err := decodeKey(keyStr, &key); err != nil {
+			return err
 		}
 
 		// 读取值
@@ -102,6 +108,60 @@ func unmarshalMap[K any, V any](mp jsonMap[K, V], data []byte) error {
 	return nil
 }
 
+// encodeKey 把键转为 JSON 对象的键，支持字符串、整数和实现了 encoding.TextMarshaler 的类型
+// 底层类型为字符串的键直接使用原始字符串，不调用 MarshalText，与 go.mod 中 Go 版本的 encoding/json 一致（json v2 会调用 MarshalText）
+func encodeKey(key any) (string, error) {
+	v := reflect.ValueOf(key)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %T", key)
+}
+
+// decodeKey 把 JSON 对象的键解析到 key 指向的值，与 encoding/json 相同，实现了 encoding.TextUnmarshaler 的类型优先调用 UnmarshalText
+func decodeKey(s string, key any) error {
+	if tu, ok := key.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	v := reflect.ValueOf(key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid map key %q: %w", s, err)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid map key %q: %w", s, err)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Interface:
+		// any 类型的键按字符串解析
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(s))
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported map key type %s", v.Type())
+}
+
 // MarshalJSON 实现json.Marshaler接口
 func marshalCollection[T any](col jsonCollection[T]) ([]byte, error) {
 	col.rlock()
@@ -0,0 +1,129 @@
+package stlx
+
+// MultiMap 是一个协程安全的一键多值映射，键按第一次写入的顺序排列，同一个键下的值按写入顺序排列
+// 零值可以直接使用
+type MultiMap[K comparable, V any] struct {
+	mp OrderedMap[K, []V]
+}
+
+// NewMultiMap 创建一个新的一键多值映射
+func NewMultiMap[K comparable, V any]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{}
+}
+
+// Set 把值追加到键已有的值之后
+func (mm *MultiMap[K, V]) Set(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	mm.mp.lock()
+	defer mm.mp.unlock()
+
+	if e, exists := mm.mp.entries[key]; exists {
+		e.value = append(e.value, values...)
+		return
+	}
+	mm.mp.set(key, append([]V(nil), values...))
+}
+
+// Get 返回键对应的所有值的副本，键不存在时返回 nil
+func (mm *MultiMap[K, V]) Get(key K) []V {
+	mm.mp.rlock()
+	defer mm.mp.runlock()
+
+	if e, exists := mm.mp.entries[key]; exists {
+		return append([]V(nil), e.value...)
+	}
+	return nil
+}
+
+// Has 判断键是否存在
+func (mm *MultiMap[K, V]) Has(key K) bool {
+	_, ok := mm.mp.Get(key)
+	return ok
+}
+
+// Count 返回键对应的值的数量
+func (mm *MultiMap[K, V]) Count(key K) int {
+	mm.mp.rlock()
+	defer mm.mp.runlock()
+
+	if e, exists := mm.mp.entries[key]; exists {
+		return len(e.value)
+	}
+	return 0
+}
+
+// Del 删除键及其所有的值，并返回被删除的值
+func (mm *MultiMap[K, V]) Del(key K) []V {
+	return mm.mp.Del(key)
+}
+
+// DelValue 删除键下第一个满足 match 的值并返回 true，键下没有值时同时删除该键
+func (mm *MultiMap[K, V]) DelValue(key K, match func(value V) bool) bool {
+	mm.mp.lock()
+	defer mm.mp.unlock()
+
+	e, exists := mm.mp.entries[key]
+	if !exists {
+		return false
+	}
+	for i, value := range e.value {
+		if !match(value) {
+			continue
+		}
+		// 复制一份新的切片，之前通过 For 拿到的切片不受影响
+		values := make([]V, 0, len(e.value)-1)
+		values = append(values, e.value[:i]...)
+		e.value = append(values, e.value[i+1:]...)
+		if len(e.value) == 0 {
+			mm.mp.remove(e)
+		}
+		return true
+	}
+	return false
+}
+
+// Len 返回键的数量
+func (mm *MultiMap[K, V]) Len() int {
+	return mm.mp.Len()
+}
+
+// Keys 按第一次写入的顺序返回所有键
+func (mm *MultiMap[K, V]) Keys() []K {
+	return mm.mp.Keys()
+}
+
+// Clear 清空映射
+func (mm *MultiMap[K, V]) Clear() {
+	mm.mp.Clear()
+}
+
+// For 按键第一次写入的顺序遍历，values 是该键所有的值，遍历过程中不能修改 values
+func (mm *MultiMap[K, V]) For(fn func(key K, values []V) bool) {
+	mm.mp.For(fn)
+}
+
+// MarshalJSON 实现json.Marshaler接口，输出为键到值数组的对象，保持键的顺序
+func (mm *MultiMap[K, V]) MarshalJSON() ([]byte, error) {
+	return mm.mp.MarshalJSON()
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口，原有内容会被清空，值为空数组或 null 的键会被跳过
+func (mm *MultiMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoded := NewUnsafeMap[K, []V]()
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	mm.mp.lock()
+	defer mm.mp.unlock()
+
+	mm.mp.clear()
+	decoded.foreach(func(key K, values []V) bool {
+		if len(values) > 0 {
+			mm.mp.set(key, values)
+		}
+		return true
+	})
+	return nil
+}
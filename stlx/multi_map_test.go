@@ -0,0 +1,82 @@
+package stlx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	mm := NewMultiMap[string, int]()
+	mm.Set("b", 1)
+	mm.Set("a", 2)
+	mm.Set("b", 3, 4)
+	mm.Set("c")
+
+	if keys := mm.Keys(); len(keys) != 2 || keys[0] != "b" || keys[1] != "a" {
+		t.Errorf("Expected keys [b a], got %v", keys)
+	}
+	if vals := mm.Get("b"); len(vals) != 3 || vals[0] != 1 || vals[2] != 4 {
+		t.Errorf("Expected [1 3 4], got %v", vals)
+	}
+	vals := mm.Get("b")
+	vals[0] = 100
+	if mm.Get("b")[0] != 1 {
+		t.Errorf("Expected Get to return a copy")
+	}
+	if mm.Get("missing") != nil || mm.Count("missing") != 0 || mm.Has("c") {
+		t.Errorf("Expected missing keys to be empty")
+	}
+
+	if !mm.DelValue("b", func(v int) bool { return v == 3 }) || mm.Count("b") != 2 {
+		t.Errorf("Expected single value to be deleted, got %v", mm.Get("b"))
+	}
+	if mm.DelValue("b", func(v int) bool { return v == 3 }) {
+		t.Errorf("Expected no match after deletion")
+	}
+	if !mm.DelValue("a", func(v int) bool { return true }) || mm.Has("a") {
+		t.Errorf("Expected key to be removed with its last value")
+	}
+
+	data, err := json.Marshal(mm)
+	if err != nil || string(data) != `{"b":[1,4]}` {
+		t.Errorf("Unexpected JSON %s, err: %v", data, err)
+	}
+	if removed := mm.Del("b"); len(removed) != 2 || mm.Len() != 0 {
+		t.Errorf("Expected whole key to be deleted, got %v", removed)
+	}
+
+	var mm2 MultiMap[string, int]
+	if err := json.Unmarshal([]byte(`{"x":[1,2],"y":[3]}`), &mm2); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if mm2.Count("x") != 2 || mm2.Keys()[1] != "y" {
+		t.Errorf("Expected JSON round-trip, got %v", mm2.Keys())
+	}
+
+	// 空数组和 null 不会产生没有值的键
+	if err := json.Unmarshal([]byte(`{"k":[],"x":[1],"n":null}`), &mm2); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if keys := mm2.Keys(); len(keys) != 1 || keys[0] != "x" || mm2.Has("k") {
+		t.Errorf("Expected empty arrays to be skipped, got %v", keys)
+	}
+	if data, err := json.Marshal(&mm2); err != nil || string(data) != `{"x":[1]}` {
+		t.Errorf("Expected round-trip without empty keys, got %s, err: %v", data, err)
+	}
+}
+
+func TestMultiMapZeroValue(t *testing.T) {
+	var mm MultiMap[string, int]
+	if mm.Get("a") != nil || mm.Len() != 0 {
+		t.Errorf("Expected empty map")
+	}
+	mm.Set("a", 1, 2)
+	mm.Set("a", 3)
+	if got := mm.Get("a"); len(got) != 3 || got[2] != 3 {
+		t.Errorf("Unexpected values %v", got)
+	}
+	var decoded MultiMap[string, int]
+	if err := json.Unmarshal([]byte(`{"b":[4]}`), &decoded); err != nil || decoded.Count("b") != 1 {
+		t.Errorf("Unexpected decode result: %v", err)
+	}
+}
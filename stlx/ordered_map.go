@@ -5,35 +5,85 @@ import (
 )
 
 // OrderedMap 是一个协程安全的有序映射，按插入顺序维护键值对
+// 键值对保存在双向链表中，map 记录键对应的节点，Set、Get、Del 都是 O(1) 的，使用 NewUnsafeMap 创建的映射不加锁
 type OrderedMap[K comparable, V any] struct {
 	mu      sync.RWMutex
-	keys    []K
-	values  []V
-	indexes map[K]int
+	nolock  bool                // 由 NewUnsafeMap 创建，不加锁
+	head    *orderedEntry[K, V] // 最早插入的节点
+	tail    *orderedEntry[K, V] // 最新插入的节点
+	entries map[K]*orderedEntry[K, V]
+	spare   []orderedEntry[K, V] // 预先分配的节点，写入新键时优先使用
+}
+
+// orderedEntry 链表中的一个键值对
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *orderedEntry[K, V]
+}
+
+// Pair 一个键值对
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
 }
 
 // NewOrderedMap 创建一个新的有序映射
 func NewMap[K comparable, V any]() *OrderedMap[K, V] {
 	return &OrderedMap[K, V]{
-		indexes: make(map[K]int),
+		entries: make(map[K]*orderedEntry[K, V]),
+	}
+}
+
+// NewMapWithCapacity 创建一个预留了 n 个键值对空间的有序映射，批量写入时避免反复扩容
+func NewMapWithCapacity[K comparable, V any](n int) *OrderedMap[K, V] {
+	om := NewMap[K, V]()
+	om.grow(n)
+	return om
+}
+
+// FromPairs 按切片的顺序创建有序映射，重复的键保留第一次出现的位置和最后一次出现的值
+func FromPairs[K comparable, V any](pairs []Pair[K, V]) *OrderedMap[K, V] {
+	om := NewMapWithCapacity[K, V](len(pairs))
+	for _, p := range pairs {
+		om.set(p.Key, p.Value)
 	}
+	return om
+}
+
+// NewUnsafeMap 创建一个不加锁的有序映射，与 NewMap 创建的映射功能完全相同
+// 省去了每次操作的加锁开销，适合只在单个协程内使用的场景，不能被多个协程同时访问
+func NewUnsafeMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		nolock:  true,
+		entries: make(map[K]*orderedEntry[K, V]),
+	}
+}
+
+// Grow 为之后写入的 n 个新键预留空间，n 小于等于0时不做任何事
+// 预留的节点成批分配，同一批中只要还有节点在使用，这一批占用的内存就不会被回收
+func (om *OrderedMap[K, V]) Grow(n int) {
+	om.lock()
+	defer om.unlock()
+
+	om.grow(n)
 }
 
 // Set 添加或更新键值对
 func (om *OrderedMap[K, V]) Set(key K, value V) {
-	om.mu.Lock()
-	defer om.mu.Unlock()
+	om.lock()
+	defer om.unlock()
 
 	om.set(key, value)
 }
 
 // Get 获取键对应的值
 func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
-	om.mu.RLock()
-	defer om.mu.RUnlock()
+	om.rlock()
+	defer om.runlock()
 
-	if index, exists := om.indexes[key]; exists {
-		return om.values[index], true
+	if e, exists := om.entries[key]; exists {
+		return e.value, true
 	}
 
 	var zero V
@@ -42,65 +92,414 @@ func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
 
 // Del 删除键值对
 func (om *OrderedMap[K, V]) Del(key K) V {
-	om.mu.Lock()
-	defer om.mu.Unlock()
+	om.lock()
+	defer om.unlock()
 
-	pos, exists := om.indexes[key]
+	e, exists := om.entries[key]
 	if !exists {
 		var zero V
 		return zero
-	} else {
-		delete(om.indexes, key)
-		val := om.values[pos]
-		om.keys = append(om.keys[:pos], om.keys[pos+1:]...)
-		om.values = append(om.values[:pos], om.values[pos+1:]...)
-		return val
 	}
+	om.remove(e)
+	return e.value
 }
 
 // Size 返回映射大小
 func (om *OrderedMap[K, V]) Len() int {
-	om.mu.RLock()
-	defer om.mu.RUnlock()
-	return len(om.keys)
+	om.rlock()
+	defer om.runlock()
+	return len(om.entries)
 }
 
 // Keys 按插入顺序返回所有键
 func (om *OrderedMap[K, V]) Keys() []K {
-	om.mu.RLock()
-	defer om.mu.RUnlock()
+	om.rlock()
+	defer om.runlock()
 
-	keys := make([]K, len(om.keys))
-	copy(keys, om.keys)
-	return keys
+	return om.keys()
 }
 
 // Vals 按插入顺序返回所有值
 func (om *OrderedMap[K, V]) Vals() []V {
-	om.mu.RLock()
-	defer om.mu.RUnlock()
+	om.rlock()
+	defer om.runlock()
 
-	values := make([]V, len(om.values))
-	copy(values, om.values)
-	return values
+	return om.values()
 }
 
 // Clear 清空映射
 func (om *OrderedMap[K, V]) Clear() {
-	om.mu.Lock()
-	defer om.mu.Unlock()
+	om.lock()
+	defer om.unlock()
 
 	om.clear()
 }
 
 // For 按顺序遍历所有键值对
 func (om *OrderedMap[K, V]) For(fn func(key K, value V) bool) {
-	om.mu.RLock()
-	defer om.mu.RUnlock()
+	om.rlock()
+	defer om.runlock()
+
+	om.foreach(fn)
+}
+
+// ForReverse 按插入顺序的逆序遍历所有键值对，从最新插入的开始
+func (om *OrderedMap[K, V]) ForReverse(fn func(key K, value V) bool) {
+	om.rlock()
+	defer om.runlock()
 
-	for i, key := range om.keys {
-		if !fn(key, om.values[i]) {
+	for e := om.tail; e != nil; e = e.prev {
+		if !fn(e.key, e.value) {
 			break
 		}
 	}
 }
+
+// GetAt 返回按插入顺序第 i 个键值对，i 越界时返回 false，需要从链表一端逐个查找
+func (om *OrderedMap[K, V]) GetAt(i int) (K, V, bool) {
+	om.rlock()
+	defer om.runlock()
+
+	if e := om.at(i); e != nil {
+		return e.key, e.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// KeyAt 返回按插入顺序第 i 个键，i 越界时返回 false
+func (om *OrderedMap[K, V]) KeyAt(i int) (K, bool) {
+	key, _, ok := om.GetAt(i)
+	return key, ok
+}
+
+// IndexOf 返回键在插入顺序中的位置，键不存在时返回 -1，需要从链表头部逐个计数
+func (om *OrderedMap[K, V]) IndexOf(key K) int {
+	om.rlock()
+	defer om.runlock()
+
+	target, exists := om.entries[key]
+	if !exists {
+		return -1
+	}
+	i := 0
+	for e := om.head; e != target; e = e.next {
+		i++
+	}
+	return i
+}
+
+// First 返回最早插入的键值对，映射为空时返回 false
+func (om *OrderedMap[K, V]) First() (K, V, bool) {
+	om.rlock()
+	defer om.runlock()
+
+	return om.entry(om.head)
+}
+
+// Last 返回最新插入的键值对，映射为空时返回 false
+func (om *OrderedMap[K, V]) Last() (K, V, bool) {
+	om.rlock()
+	defer om.runlock()
+
+	return om.entry(om.tail)
+}
+
+// PopFirst 删除并返回最早插入的键值对，映射为空时返回 false
+func (om *OrderedMap[K, V]) PopFirst() (K, V, bool) {
+	om.lock()
+	defer om.unlock()
+
+	return om.pop(om.head)
+}
+
+// PopLast 删除并返回最新插入的键值对，映射为空时返回 false
+func (om *OrderedMap[K, V]) PopLast() (K, V, bool) {
+	om.lock()
+	defer om.unlock()
+
+	return om.pop(om.tail)
+}
+
+// MoveToFront 把键移到最前面，键不存在时返回 false
+func (om *OrderedMap[K, V]) MoveToFront(key K) bool {
+	om.lock()
+	defer om.unlock()
+
+	e, exists := om.entries[key]
+	if !exists {
+		return false
+	}
+	if e != om.head {
+		om.unlink(e)
+		om.insertAfter(e, nil)
+	}
+	return true
+}
+
+// MoveToBack 把键移到最后面，键不存在时返回 false
+func (om *OrderedMap[K, V]) MoveToBack(key K) bool {
+	om.lock()
+	defer om.unlock()
+
+	e, exists := om.entries[key]
+	if !exists {
+		return false
+	}
+	if e != om.tail {
+		om.unlink(e)
+		om.insertAfter(e, om.tail)
+	}
+	return true
+}
+
+// MoveAfter 把键移到 mark 之后，任一键不存在或两者相同时返回 false
+func (om *OrderedMap[K, V]) MoveAfter(key, mark K) bool {
+	om.lock()
+	defer om.unlock()
+
+	e, exists := om.entries[key]
+	markEntry, markExists := om.entries[mark]
+	if !exists || !markExists || e == markEntry {
+		return false
+	}
+	om.unlink(e)
+	om.insertAfter(e, markEntry)
+	return true
+}
+
+// SortKeys 按键重新排列映射，排序是稳定的，相等的键保持原有的相对顺序
+func (om *OrderedMap[K, V]) SortKeys(less func(a, b K) bool) {
+	om.lock()
+	defer om.unlock()
+
+	om.sort(func(a, b *orderedEntry[K, V]) bool { return less(a.key, b.key) })
+}
+
+// SortByValue 按值重新排列映射，排序是稳定的，值相等的键保持原有的相对顺序
+func (om *OrderedMap[K, V]) SortByValue(less func(a, b V) bool) {
+	om.lock()
+	defer om.unlock()
+
+	om.sort(func(a, b *orderedEntry[K, V]) bool { return less(a.value, b.value) })
+}
+
+// Clone 返回映射的副本，副本与原映射互不影响，值本身按赋值复制，副本是否加锁与原映射一致
+func (om *OrderedMap[K, V]) Clone() *OrderedMap[K, V] {
+	om.rlock()
+	defer om.runlock()
+
+	clone := &OrderedMap[K, V]{
+		nolock:  om.nolock,
+		entries: make(map[K]*orderedEntry[K, V], len(om.entries)),
+	}
+	om.foreach(func(key K, value V) bool {
+		clone.set(key, value)
+		return true
+	})
+	return clone
+}
+
+// Merge 把 other 中的键值对合并到映射中，新的键按在 other 中的顺序追加到末尾
+// 两边都存在的键保持原有位置，值由 onConflict 决定，onConflict 为 nil 时使用 other 中的值
+// other 可以是映射自身，此时顺序不变，只按 onConflict 重新计算每个值
+func (om *OrderedMap[K, V]) Merge(other *OrderedMap[K, V], onConflict func(key K, old, new V) V) {
+	keys, values := other.snapshot()
+
+	om.lock()
+	defer om.unlock()
+
+	for i, key := range keys {
+		if e, exists := om.entries[key]; exists {
+			if onConflict != nil {
+				e.value = onConflict(key, e.value, values[i])
+			} else {
+				e.value = values[i]
+			}
+			continue
+		}
+		om.set(key, values[i])
+	}
+}
+
+// Filter 返回只包含满足 pred 的键值对的新映射，保持原有顺序
+func (om *OrderedMap[K, V]) Filter(pred func(key K, value V) bool) *OrderedMap[K, V] {
+	om.rlock()
+	defer om.runlock()
+
+	result := &OrderedMap[K, V]{
+		nolock:  om.nolock,
+		entries: make(map[K]*orderedEntry[K, V]),
+	}
+	om.foreach(func(key K, value V) bool {
+		if pred(key, value) {
+			result.set(key, value)
+		}
+		return true
+	})
+	return result
+}
+
+// MapValues 返回键和顺序不变、值经过 fn 转换的新映射
+// 方法不能带类型参数，转换为其他值类型的映射需要以函数形式调用
+func MapValues[K comparable, V any, R any](om *OrderedMap[K, V], fn func(key K, value V) R) *OrderedMap[K, R] {
+	om.rlock()
+	defer om.runlock()
+
+	result := &OrderedMap[K, R]{
+		nolock:  om.nolock,
+		entries: make(map[K]*orderedEntry[K, R], len(om.entries)),
+	}
+	om.foreach(func(key K, value V) bool {
+		result.set(key, fn(key, value))
+		return true
+	})
+	return result
+}
+
+// Reduce 按插入顺序把所有键值对依次累积到 init 上并返回结果
+func Reduce[K comparable, V any, A any](om *OrderedMap[K, V], init A, fn func(acc A, key K, value V) A) A {
+	om.rlock()
+	defer om.runlock()
+
+	acc := init
+	om.foreach(func(key K, value V) bool {
+		acc = fn(acc, key, value)
+		return true
+	})
+	return acc
+}
+
+// GetOrSet 键存在时返回已有的值和 true，否则写入 value 并返回 value 和 false
+func (om *OrderedMap[K, V]) GetOrSet(key K, value V) (V, bool) {
+	om.lock()
+	defer om.unlock()
+
+	if e, exists := om.entries[key]; exists {
+		return e.value, true
+	}
+	om.set(key, value)
+	return value, false
+}
+
+// GetOrSetFunc 键存在时返回已有的值，否则调用 fn 生成值并写入
+// fn 在写锁内执行，同一时刻只有一个协程能为键生成值，fn 内不能再访问该映射
+func (om *OrderedMap[K, V]) GetOrSetFunc(key K, fn func() V) V {
+	om.lock()
+	defer om.unlock()
+
+	if e, exists := om.entries[key]; exists {
+		return e.value
+	}
+	value := fn()
+	om.set(key, value)
+	return value
+}
+
+// SetIfAbsent 键不存在时写入并返回 true，键已存在时保持原值并返回 false
+func (om *OrderedMap[K, V]) SetIfAbsent(key K, value V) bool {
+	om.lock()
+	defer om.unlock()
+
+	if _, exists := om.entries[key]; exists {
+		return false
+	}
+	om.set(key, value)
+	return true
+}
+
+// Replace 键存在时替换值并返回 true，位置保持不变，键不存在时不写入并返回 false
+func (om *OrderedMap[K, V]) Replace(key K, value V) bool {
+	om.lock()
+	defer om.unlock()
+
+	e, exists := om.entries[key]
+	if !exists {
+		return false
+	}
+	e.value = value
+	return true
+}
+
+// Swap 写入值并返回原来的值，loaded 表示键之前是否存在，已存在的键保持原有位置
+func (om *OrderedMap[K, V]) Swap(key K, value V) (old V, loaded bool) {
+	om.lock()
+	defer om.unlock()
+
+	if e, exists := om.entries[key]; exists {
+		old, e.value = e.value, value
+		return old, true
+	}
+	om.set(key, value)
+	return old, false
+}
+
+// Entries 按插入顺序返回所有键值对
+func (om *OrderedMap[K, V]) Entries() []Pair[K, V] {
+	om.rlock()
+	defer om.runlock()
+
+	pairs := make([]Pair[K, V], 0, len(om.entries))
+	for e := om.head; e != nil; e = e.next {
+		pairs = append(pairs, Pair[K, V]{Key: e.key, Value: e.value})
+	}
+	return pairs
+}
+
+// ToMap 返回包含所有键值对的普通 map，顺序信息会丢失
+func (om *OrderedMap[K, V]) ToMap() map[K]V {
+	om.rlock()
+	defer om.runlock()
+
+	m := make(map[K]V, len(om.entries))
+	for key, e := range om.entries {
+		m[key] = e.value
+	}
+	return m
+}
+
+// Equal 判断两个映射是否包含相同的键值对且顺序一致，值使用 eq 比较，与自身比较时直接返回 true
+func (om *OrderedMap[K, V]) Equal(other *OrderedMap[K, V], eq func(a, b V) bool) bool {
+	if om == other {
+		return true
+	}
+	keys, values := other.snapshot()
+
+	om.rlock()
+	defer om.runlock()
+
+	if len(keys) != len(om.entries) {
+		return false
+	}
+	i := 0
+	for e := om.head; e != nil; e = e.next {
+		if e.key != keys[i] || !eq(e.value, values[i]) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// EqualUnordered 判断两个映射是否包含相同的键值对，不比较顺序，值使用 eq 比较
+func (om *OrderedMap[K, V]) EqualUnordered(other *OrderedMap[K, V], eq func(a, b V) bool) bool {
+	if om == other {
+		return true
+	}
+	keys, values := other.snapshot()
+
+	om.rlock()
+	defer om.runlock()
+
+	if len(keys) != len(om.entries) {
+		return false
+	}
+	for i, key := range keys {
+		e, exists := om.entries[key]
+		if !exists || !eq(e.value, values[i]) {
+			return false
+		}
+	}
+	return true
+}
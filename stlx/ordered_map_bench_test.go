@@ -0,0 +1,38 @@
+package stlx
+
+import "testing"
+
+// BenchmarkOrderedMap 对比加锁与不加锁的映射在单协程热循环中的开销
+func BenchmarkOrderedMap(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		new  func() *OrderedMap[int, int]
+	}{
+		{"Locked", NewMap[int, int]},
+		{"Unsafe", NewUnsafeMap[int, int]},
+	} {
+		b.Run(bc.name+"/Set", func(b *testing.B) {
+			om := bc.new()
+			for i := 0; i < b.N; i++ {
+				om.Set(i&1023, i)
+			}
+		})
+		b.Run(bc.name+"/Get", func(b *testing.B) {
+			om := bc.new()
+			for i := 0; i < 1024; i++ {
+				om.Set(i, i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				om.Get(i & 1023)
+			}
+		})
+		b.Run(bc.name+"/SetDel", func(b *testing.B) {
+			om := bc.new()
+			for i := 0; i < b.N; i++ {
+				om.Set(i, i)
+				om.Del(i)
+			}
+		})
+	}
+}
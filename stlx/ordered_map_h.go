@@ -1,53 +1,282 @@
 package stlx
 
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
 func (om *OrderedMap[K, V]) clear() {
 
-	om.keys = nil
-	om.values = nil
-	om.indexes = make(map[K]int)
+	om.head = nil
+	om.tail = nil
+	om.entries = make(map[K]*orderedEntry[K, V])
+	om.spare = nil
 }
 
 func (om *OrderedMap[K, V]) set(key K, value V) {
-	if index, exists := om.indexes[key]; exists {
+	if e, exists := om.entries[key]; exists {
 		// 如果键已存在，只更新值
-		om.values[index] = value
+		e.value = value
+		return
+	}
+
+	// 添加到映射，零值的映射在第一次写入时初始化
+	if om.entries == nil {
+		om.entries = make(map[K]*orderedEntry[K, V])
+	}
+	e := om.newEntry()
+	e.key, e.value = key, value
+	om.entries[key] = e
+	om.insertAfter(e, om.tail)
+}
+
+// newEntry 分配一个节点，有预留的节点时直接使用
+func (om *OrderedMap[K, V]) newEntry() *orderedEntry[K, V] {
+	if len(om.spare) == 0 {
+		return &orderedEntry[K, V]{}
+	}
+	e := &om.spare[0]
+	om.spare = om.spare[1:]
+	return e
+}
+
+// grow 扩大 entries 的容量并预留 n 个节点
+func (om *OrderedMap[K, V]) grow(n int) {
+	if n <= 0 || len(om.spare) >= n {
 		return
 	}
+	entries := make(map[K]*orderedEntry[K, V], len(om.entries)+n)
+	for key, e := range om.entries {
+		entries[key] = e
+	}
+	om.entries = entries
+	om.spare = make([]orderedEntry[K, V], n)
+}
+
+func (om *OrderedMap[K, V]) keys() []K {
+	keys := make([]K, 0, len(om.entries))
+	for e := om.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (om *OrderedMap[K, V]) values() []V {
+	values := make([]V, 0, len(om.entries))
+	for e := om.head; e != nil; e = e.next {
+		values = append(values, e.value)
+	}
+	return values
+}
+
+// at 返回第 i 个节点，从离 i 较近的一端开始查找，越界时返回 nil
+func (om *OrderedMap[K, V]) at(i int) *orderedEntry[K, V] {
+	n := len(om.entries)
+	if i < 0 || i >= n {
+		return nil
+	}
+	if i < n/2 {
+		e := om.head
+		for ; i > 0; i-- {
+			e = e.next
+		}
+		return e
+	}
+	e := om.tail
+	for i = n - 1 - i; i > 0; i-- {
+		e = e.prev
+	}
+	return e
+}
+
+func (om *OrderedMap[K, V]) entry(e *orderedEntry[K, V]) (K, V, bool) {
+	if e == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return e.key, e.value, true
+}
+
+func (om *OrderedMap[K, V]) pop(e *orderedEntry[K, V]) (K, V, bool) {
+	if e != nil {
+		om.remove(e)
+	}
+	return om.entry(e)
+}
 
-	// 添加到映射
-	om.keys = append(om.keys, key)
-	om.values = append(om.values, value)
-	om.indexes[key] = len(om.keys) - 1
+// remove 从映射和链表中删除节点
+func (om *OrderedMap[K, V]) remove(e *orderedEntry[K, V]) {
+	delete(om.entries, e.key)
+	om.unlink(e)
+}
+
+// unlink 把节点从链表中摘下，不修改 entries
+func (om *OrderedMap[K, V]) unlink(e *orderedEntry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		om.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		om.tail = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+// insertAfter 把节点插入到 mark 之后，mark 为 nil 时插入到最前面
+func (om *OrderedMap[K, V]) insertAfter(e, mark *orderedEntry[K, V]) {
+	e.prev = mark
+	if mark != nil {
+		e.next = mark.next
+		mark.next = e
+	} else {
+		e.next = om.head
+		om.head = e
+	}
+	if e.next != nil {
+		e.next.prev = e
+	} else {
+		om.tail = e
+	}
+}
+
+// sort 按 less 稳定排序所有节点并重新连接链表
+func (om *OrderedMap[K, V]) sort(less func(a, b *orderedEntry[K, V]) bool) {
+	entries := make([]*orderedEntry[K, V], 0, len(om.entries))
+	for e := om.head; e != nil; e = e.next {
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+	om.head, om.tail = nil, nil
+	for _, e := range entries {
+		om.insertAfter(e, om.tail)
+	}
 }
 
 func (om *OrderedMap[K, V]) foreach(fn func(key K, value V) bool) {
-	for i, key := range om.keys {
-		if !fn(key, om.values[i]) {
+	for e := om.head; e != nil; e = e.next {
+		if !fn(e.key, e.value) {
 			break
 		}
 	}
 }
 
 func (om *OrderedMap[K, V]) lock() {
-	om.mu.Lock()
+	if !om.nolock {
+		om.mu.Lock()
+	}
 }
 
 func (om *OrderedMap[K, V]) unlock() {
-	om.mu.Unlock()
+	if !om.nolock {
+		om.mu.Unlock()
+	}
 }
 
 func (om *OrderedMap[K, V]) rlock() {
-	om.mu.RLock()
+	if !om.nolock {
+		om.mu.RLock()
+	}
 }
 
 func (om *OrderedMap[K, V]) runlock() {
-	om.mu.RUnlock()
+	if !om.nolock {
+		om.mu.RUnlock()
+	}
 }
 
+// MarshalJSON 实现json.Marshaler接口，按插入顺序输出键值对
 func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
 	return marshalMap[K, V](om)
 }
 
+// UnmarshalJSON 实现json.Unmarshaler接口，按 JSON 中键出现的顺序插入，原有内容会被清空
 func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
 	return unmarshalMap[K, V](om, data)
 }
+
+// GobEncode 实现gob.GobEncoder接口，依次编码按插入顺序排列的键和值
+func (om *OrderedMap[K, V]) GobEncode() ([]byte, error) {
+	om.rlock()
+	defer om.runlock()
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(om.keys()); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(om.values()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode 实现gob.GobDecoder接口，按编码时的顺序恢复键值对，原有内容会被清空
+func (om *OrderedMap[K, V]) GobDecode(data []byte) error {
+	var keys []K
+	var values []V
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&keys); err != nil {
+		return err
+	}
+	if err := dec.Decode(&values); err != nil {
+		return err
+	}
+	if len(keys) != len(values) {
+		return fmt.Errorf("gob: %d keys but %d values", len(keys), len(values))
+	}
+
+	om.lock()
+	defer om.unlock()
+
+	om.clear()
+	for i, key := range keys {
+		om.set(key, values[i])
+	}
+	return nil
+}
+
+// String 实现fmt.Stringer接口，按插入顺序输出 {k1: v1, k2: v2}
+func (om *OrderedMap[K, V]) String() string {
+	om.rlock()
+	defer om.runlock()
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for e := om.head; e != nil; e = e.next {
+		if e != om.head {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%v: %v", e.key, e.value)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// PrettyJSON 返回按插入顺序缩进输出的 JSON，每一层使用 indent 缩进
+func (om *OrderedMap[K, V]) PrettyJSON(indent string) ([]byte, error) {
+	data, err := om.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshot 在读锁下复制键和值，供迭代器在不持有锁的情况下遍历
+func (om *OrderedMap[K, V]) snapshot() ([]K, []V) {
+	om.rlock()
+	defer om.runlock()
+
+	return om.keys(), om.values()
+}
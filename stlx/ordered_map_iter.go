@@ -0,0 +1,52 @@
+package stlx
+
+import "iter"
+
+// All 返回按插入顺序遍历所有键值对的迭代器，可用于 for k, v := range om.All()
+// 开始遍历时在读锁下复制一份键值，遍历过程中不持有锁，循环体内可以修改映射，修改不影响本次遍历
+func (om *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := om.snapshot()
+		for i, key := range keys {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// KeysSeq 返回按插入顺序遍历所有键的迭代器，快照规则与 All 相同
+func (om *OrderedMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys, _ := om.snapshot()
+		for _, key := range keys {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq 返回按插入顺序遍历所有值的迭代器，快照规则与 All 相同
+func (om *OrderedMap[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		_, values := om.snapshot()
+		for _, value := range values {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 返回从最新插入到最早插入逆序遍历键值对的迭代器，快照规则与 All 相同
+func (om *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		keys, values := om.snapshot()
+		for i := len(keys) - 1; i >= 0; i-- {
+			if !yield(keys[i], values[i]) {
+				return
+			}
+		}
+	}
+}
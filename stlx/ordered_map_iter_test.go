@@ -0,0 +1,60 @@
+package stlx
+
+import "testing"
+
+func TestOrderedMapIter(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	var keys []string
+	sum := 0
+	for k, v := range om.All() {
+		keys = append(keys, k)
+		sum += v
+	}
+	if len(keys) != 3 || keys[0] != "a" || keys[2] != "c" || sum != 6 {
+		t.Errorf("Unexpected iteration: %v, sum %d", keys, sum)
+	}
+
+	for k := range om.KeysSeq() {
+		if k == "b" {
+			break
+		}
+		if k != "a" {
+			t.Errorf("Expected break after b, got %s", k)
+		}
+	}
+	values := []int{}
+	for v := range om.ValuesSeq() {
+		values = append(values, v)
+	}
+	if len(values) != 3 || values[1] != 2 {
+		t.Errorf("Expected values [1 2 3], got %v", values)
+	}
+
+	// 遍历过程中修改映射不会死锁，也不影响本次遍历
+	n := 0
+	for k := range om.All() {
+		om.Set(k+"x", 0)
+		n++
+	}
+	if n != 3 || om.Len() != 6 {
+		t.Errorf("Expected 3 iterations and 6 entries, got %d and %d", n, om.Len())
+	}
+}
+
+func TestOrderedMapBackward(t *testing.T) {
+	om := NewMap[int, string]()
+	for i := 1; i <= 3; i++ {
+		om.Set(i, "")
+	}
+	var keys []int
+	for k := range om.Backward() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 3 || keys[0] != 3 || keys[1] != 2 || keys[2] != 1 {
+		t.Errorf("Expected [3 2 1], got %v", keys)
+	}
+}
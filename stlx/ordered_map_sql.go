@@ -0,0 +1,36 @@
+package stlx
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value 实现driver.Valuer接口，以保持插入顺序的 JSON 字符串写入 JSON 或 JSONB 列，nil 写入 NULL
+func (om *OrderedMap[K, V]) Value() (driver.Value, error) {
+	if om == nil {
+		return nil, nil
+	}
+	data, err := om.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan 实现sql.Scanner接口，从 JSON 列读取映射，NULL 读取为空映射
+// JSONB 列在数据库中不保存键的顺序，读取到的顺序由数据库决定
+func (om *OrderedMap[K, V]) Scan(src any) error {
+	switch data := src.(type) {
+	case nil:
+		om.lock()
+		defer om.unlock()
+		om.clear()
+		return nil
+	case []byte:
+		return om.UnmarshalJSON(data)
+	case string:
+		return om.UnmarshalJSON([]byte(data))
+	default:
+		return fmt.Errorf("stlx: cannot scan %T into OrderedMap", src)
+	}
+}
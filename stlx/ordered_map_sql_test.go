@@ -0,0 +1,43 @@
+package stlx
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = (*OrderedMap[string, int])(nil)
+	_ sql.Scanner   = (*OrderedMap[string, int])(nil)
+)
+
+func TestOrderedMapSQL(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	v, err := om.Value()
+	if err != nil || v != `{"b":2,"a":1}` {
+		t.Errorf("Unexpected value %v, err: %v", v, err)
+	}
+	var nilMap *OrderedMap[string, int]
+	if v, err := nilMap.Value(); v != nil || err != nil {
+		t.Errorf("Expected NULL for nil map, got %v, err: %v", v, err)
+	}
+
+	got := NewMap[string, int]()
+	if err := got.Scan([]byte(`{"z":26,"y":25}`)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if keys := got.Keys(); len(keys) != 2 || keys[0] != "z" {
+		t.Errorf("Expected order [z y], got %v", keys)
+	}
+	if err := got.Scan(`{"x":24}`); err != nil || got.Len() != 1 {
+		t.Errorf("Expected string source to replace content, len %d, err: %v", got.Len(), err)
+	}
+	if err := got.Scan(nil); err != nil || got.Len() != 0 {
+		t.Errorf("Expected NULL to clear the map, len %d, err: %v", got.Len(), err)
+	}
+	if err := got.Scan(42); err == nil {
+		t.Errorf("Expected error for unsupported source")
+	}
+}
@@ -1,7 +1,14 @@
 package stlx
 
 import (
+	"bytes"
+	"encoding/gob"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -34,3 +41,530 @@ func TestOrderedMap(t *testing.T) {
 	})
 
 }
+
+func TestOrderedMapJSONOrder(t *testing.T) {
+	om := NewMap[string, int]()
+	for _, key := range []string{"z", "a", `q"uote`, "m"} {
+		om.Set(key, len(key))
+	}
+	data, err := json.Marshal(om)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if want := `{"z":1,"a":1,"q\"uote":6,"m":1}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	om2 := NewMap[string, int]()
+	if err := json.Unmarshal([]byte(`{"c":3,"b":2,"a":1,"b":4}`), om2); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	keys := om2.Keys()
+	if len(keys) != 3 || keys[0] != "c" || keys[1] != "b" || keys[2] != "a" {
+		t.Errorf("Expected order [c b a], got %v", keys)
+	}
+	if v, _ := om2.Get("b"); v != 4 {
+		t.Errorf("Expected duplicate key to keep the last value, got %d", v)
+	}
+
+	var om3 OrderedMap[string, int]
+	if err := json.Unmarshal(data, &om3); err != nil {
+		t.Fatalf("UnmarshalJSON into zero value failed: %v", err)
+	}
+	if v, ok := om3.Get(`q"uote`); !ok || v != 6 {
+		t.Errorf("Expected escaped key to round-trip, got %v, ok: %v", v, ok)
+	}
+}
+
+func TestOrderedMapJSONIntKeys(t *testing.T) {
+	om := NewMap[int, string]()
+	om.Set(3, "c")
+	om.Set(-1, "a")
+	data, err := json.Marshal(om)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if want := `{"3":"c","-1":"a"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+	om2 := NewMap[int, string]()
+	if err := json.Unmarshal(data, om2); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if keys := om2.Keys(); len(keys) != 2 || keys[0] != 3 || keys[1] != -1 {
+		t.Errorf("Expected keys [3 -1], got %v", keys)
+	}
+	if err := json.Unmarshal([]byte(`{"x":"a"}`), om2); err == nil {
+		t.Errorf("Expected error for non-numeric key")
+	}
+	if err := json.Unmarshal([]byte(`[1]`), om2); err == nil {
+		t.Errorf("Expected error for non-object input")
+	}
+}
+
+// upperKey 底层类型为字符串并实现了 encoding.TextMarshaler 的键
+type upperKey string
+
+func (k upperKey) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(k))), nil
+}
+
+func TestOrderedMapJSONStringKindKeys(t *testing.T) {
+	om := NewMap[upperKey, int]()
+	om.Set("a", 1)
+	data, err := json.Marshal(om)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	// 使用原始字符串而不是 MarshalText 的结果
+	if want := `{"a":1}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestOrderedMapForReverse(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	var keys []string
+	om.ForReverse(func(key string, value int) bool {
+		keys = append(keys, key)
+		return key != "b"
+	})
+	if len(keys) != 2 || keys[0] != "c" || keys[1] != "b" {
+		t.Errorf("Expected [c b], got %v", keys)
+	}
+}
+
+func TestOrderedMapIndex(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	om.Set("d", 4)
+
+	if k, v, ok := om.GetAt(2); !ok || k != "c" || v != 3 {
+		t.Errorf("Expected c=3 at 2, got %s=%d, ok: %v", k, v, ok)
+	}
+	if _, _, ok := om.GetAt(4); ok {
+		t.Errorf("Expected out of range index to fail")
+	}
+	if _, ok := om.KeyAt(-1); ok {
+		t.Errorf("Expected negative index to fail")
+	}
+
+	// 删除后其后的键前移，位置和取值保持一致
+	if v := om.Del("b"); v != 2 {
+		t.Errorf("Expected deleted value 2, got %d", v)
+	}
+	if i := om.IndexOf("d"); i != 2 {
+		t.Errorf("Expected d at 2 after deletion, got %d", i)
+	}
+	if v, ok := om.Get("d"); !ok || v != 4 {
+		t.Errorf("Expected d=4 after deletion, got %d, ok: %v", v, ok)
+	}
+	if k, ok := om.KeyAt(1); !ok || k != "c" {
+		t.Errorf("Expected c at 1, got %s", k)
+	}
+	if i := om.IndexOf("b"); i != -1 {
+		t.Errorf("Expected -1 for deleted key, got %d", i)
+	}
+}
+
+func TestOrderedMapPop(t *testing.T) {
+	om := NewMap[string, int]()
+	if _, _, ok := om.First(); ok {
+		t.Errorf("Expected First on empty map to fail")
+	}
+	if _, _, ok := om.PopLast(); ok {
+		t.Errorf("Expected PopLast on empty map to fail")
+	}
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+
+	if k, v, ok := om.First(); !ok || k != "a" || v != 1 {
+		t.Errorf("Expected first a=1, got %s=%d", k, v)
+	}
+	if k, v, ok := om.Last(); !ok || k != "c" || v != 3 {
+		t.Errorf("Expected last c=3, got %s=%d", k, v)
+	}
+	if k, v, ok := om.PopFirst(); !ok || k != "a" || v != 1 {
+		t.Errorf("Expected to pop a=1, got %s=%d", k, v)
+	}
+	if k, v, ok := om.PopLast(); !ok || k != "c" || v != 3 {
+		t.Errorf("Expected to pop c=3, got %s=%d", k, v)
+	}
+	if om.Len() != 1 || om.IndexOf("b") != 0 {
+		t.Errorf("Expected only b at 0, got len %d index %d", om.Len(), om.IndexOf("b"))
+	}
+	om.Set("d", 4)
+	if v, _ := om.Get("d"); v != 4 {
+		t.Errorf("Expected d=4 after pops, got %d", v)
+	}
+}
+
+func TestOrderedMapMove(t *testing.T) {
+	om := NewMap[string, int]()
+	for i, key := range []string{"a", "b", "c", "d"} {
+		om.Set(key, i)
+	}
+	check := func(want string) {
+		t.Helper()
+		got := ""
+		for i, key := range om.Keys() {
+			if om.IndexOf(key) != i {
+				t.Errorf("Index of %s out of sync", key)
+			}
+			got += key
+		}
+		if got != want {
+			t.Errorf("Expected order %s, got %s", want, got)
+		}
+	}
+
+	om.MoveToFront("c")
+	check("cabd")
+	om.MoveToBack("a")
+	check("cbda")
+	om.MoveAfter("c", "d")
+	check("bdca")
+	om.MoveAfter("a", "b")
+	check("badc")
+	if om.MoveAfter("a", "a") || om.MoveToFront("x") || om.MoveAfter("a", "x") {
+		t.Errorf("Expected invalid moves to fail")
+	}
+	check("badc")
+	if v, _ := om.Get("c"); v != 2 {
+		t.Errorf("Expected values to move with keys, got %d", v)
+	}
+}
+
+func TestOrderedMapSort(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("c", 1)
+	om.Set("a", 3)
+	om.Set("d", 1)
+	om.Set("b", 2)
+
+	om.SortKeys(func(a, b string) bool { return a < b })
+	if keys := om.Keys(); keys[0] != "a" || keys[1] != "b" || keys[2] != "c" || keys[3] != "d" {
+		t.Errorf("Expected sorted keys, got %v", keys)
+	}
+	if v, _ := om.Get("a"); v != 3 || om.IndexOf("d") != 3 {
+		t.Errorf("Expected values and indexes to follow keys")
+	}
+
+	om.SortByValue(func(a, b int) bool { return a < b })
+	// 值相等的 c 和 d 保持排序前的相对顺序
+	if keys := om.Keys(); keys[0] != "c" || keys[1] != "d" || keys[2] != "b" || keys[3] != "a" {
+		t.Errorf("Expected keys ordered by value, got %v", keys)
+	}
+	if om.IndexOf("b") != 2 {
+		t.Errorf("Expected b at 2, got %d", om.IndexOf("b"))
+	}
+}
+
+func TestOrderedMapClone(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	clone := om.Clone()
+	clone.Set("a", 10)
+	clone.Set("c", 3)
+	clone.Del("b")
+	if v, _ := om.Get("a"); v != 1 || om.Len() != 2 {
+		t.Errorf("Expected original to be unchanged, got a=%d len %d", v, om.Len())
+	}
+	if keys := clone.Keys(); len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Errorf("Expected clone keys [a c], got %v", keys)
+	}
+	if om.IndexOf("b") != 1 {
+		t.Errorf("Expected original index to be unchanged")
+	}
+}
+
+func TestOrderedMapMerge(t *testing.T) {
+	base := NewMap[string, int]()
+	base.Set("a", 1)
+	base.Set("b", 2)
+	layer := NewMap[string, int]()
+	layer.Set("c", 30)
+	layer.Set("b", 20)
+	layer.Set("d", 40)
+
+	base.Merge(layer, func(key string, old, new int) int { return old + new })
+	if keys := base.Keys(); len(keys) != 4 || keys[2] != "c" || keys[3] != "d" {
+		t.Errorf("Expected new keys appended in order, got %v", keys)
+	}
+	if v, _ := base.Get("b"); v != 22 {
+		t.Errorf("Expected conflict resolved to 22, got %d", v)
+	}
+
+	base.Merge(layer, nil)
+	if v, _ := base.Get("b"); v != 20 || base.Len() != 4 {
+		t.Errorf("Expected other value to win without callback, got %d", v)
+	}
+	base.Merge(base, nil)
+	if base.Len() != 4 {
+		t.Errorf("Expected merging with itself to be a no-op, got len %d", base.Len())
+	}
+}
+
+func TestOrderedMapTransform(t *testing.T) {
+	om := NewMap[string, int]()
+	om.Set("c", 3)
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	odd := om.Filter(func(key string, value int) bool { return value%2 == 1 })
+	if keys := odd.Keys(); len(keys) != 2 || keys[0] != "c" || keys[1] != "a" {
+		t.Errorf("Expected [c a], got %v", keys)
+	}
+	odd.Set("z", 0)
+	if om.Len() != 3 {
+		t.Errorf("Expected filtered map to be independent")
+	}
+
+	labels := MapValues(om, func(key string, value int) string { return key + strconv.Itoa(value) })
+	if vals := labels.Vals(); len(vals) != 3 || vals[0] != "c3" || vals[2] != "b2" {
+		t.Errorf("Expected [c3 a1 b2], got %v", vals)
+	}
+	if labels.IndexOf("a") != 1 {
+		t.Errorf("Expected mapped indexes to match")
+	}
+
+	joined := Reduce(om, "", func(acc string, key string, value int) string { return acc + key })
+	if joined != "cab" {
+		t.Errorf("Expected cab, got %s", joined)
+	}
+}
+
+func TestOrderedMapDelConsistency(t *testing.T) {
+	om := NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		om.Set(i, i*10)
+	}
+	for i := 0; i < 100; i += 3 {
+		om.Del(i)
+	}
+	om.Del(1000)
+	for i := 0; i < 100; i++ {
+		v, ok := om.Get(i)
+		if ok != (i%3 != 0) || (ok && v != i*10) {
+			t.Fatalf("Unexpected Get(%d) = %d, %v after deletions", i, v, ok)
+		}
+	}
+	prev := -1
+	om.For(func(key, value int) bool {
+		if key <= prev || key%3 == 0 {
+			t.Fatalf("Unexpected key %d after %d", key, prev)
+		}
+		prev = key
+		return true
+	})
+	if k, _, _ := om.Last(); k != 98 {
+		t.Errorf("Expected last key 98, got %d", k)
+	}
+	for om.Len() > 0 {
+		om.PopFirst()
+	}
+	om.Set(1, 1)
+	if k, _, ok := om.First(); !ok || k != 1 {
+		t.Errorf("Expected map to be reusable after draining")
+	}
+}
+
+func TestUnsafeMap(t *testing.T) {
+	om := NewUnsafeMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Del("b")
+	om.Set("c", 3)
+	if keys := om.Keys(); len(keys) != 2 || keys[0] != "a" || keys[1] != "c" {
+		t.Errorf("Expected [a c], got %v", keys)
+	}
+	if clone := om.Clone(); !clone.nolock {
+		t.Errorf("Expected clone of unsafe map to be unsafe")
+	}
+	data, err := json.Marshal(om)
+	if err != nil || string(data) != `{"a":1,"c":3}` {
+		t.Errorf("Unexpected JSON %s, err: %v", data, err)
+	}
+}
+
+func TestOrderedMapGetOrSet(t *testing.T) {
+	om := NewMap[string, int]()
+	if v, loaded := om.GetOrSet("a", 1); loaded || v != 1 {
+		t.Errorf("Expected to store 1, got %d, loaded: %v", v, loaded)
+	}
+	if v, loaded := om.GetOrSet("a", 2); !loaded || v != 1 {
+		t.Errorf("Expected existing 1, got %d, loaded: %v", v, loaded)
+	}
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := om.GetOrSetFunc("b", func() int { calls.Add(1); return 2 }); v != 2 {
+				t.Errorf("Expected 2, got %d", v)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected fn to run once, got %d", n)
+	}
+	if keys := om.Keys(); len(keys) != 2 || keys[1] != "b" {
+		t.Errorf("Expected [a b], got %v", keys)
+	}
+}
+
+func TestOrderedMapConditionalSet(t *testing.T) {
+	om := NewMap[string, int]()
+	if om.Replace("a", 1) {
+		t.Errorf("Expected Replace of missing key to fail")
+	}
+	if !om.SetIfAbsent("a", 1) || om.SetIfAbsent("a", 2) {
+		t.Errorf("Expected SetIfAbsent to write only once")
+	}
+	om.Set("b", 2)
+	if !om.Replace("a", 10) {
+		t.Errorf("Expected Replace of existing key to succeed")
+	}
+	if old, loaded := om.Swap("a", 20); !loaded || old != 10 {
+		t.Errorf("Expected old value 10, got %d, loaded: %v", old, loaded)
+	}
+	if old, loaded := om.Swap("c", 3); loaded || old != 0 {
+		t.Errorf("Expected new key, got %d, loaded: %v", old, loaded)
+	}
+	if keys := om.Keys(); len(keys) != 3 || keys[0] != "a" || keys[2] != "c" {
+		t.Errorf("Expected positions to be kept, got %v", keys)
+	}
+	if v, _ := om.Get("a"); v != 20 {
+		t.Errorf("Expected 20, got %d", v)
+	}
+}
+
+func TestOrderedMapString(t *testing.T) {
+	om := NewMap[string, int]()
+	if s := om.String(); s != "{}" {
+		t.Errorf("Expected {}, got %s", s)
+	}
+	om.Set("b", 2)
+	om.Set("a", 1)
+	if s := fmt.Sprint(om); s != "{b: 2, a: 1}" {
+		t.Errorf("Expected {b: 2, a: 1}, got %s", s)
+	}
+	data, err := om.PrettyJSON("  ")
+	if err != nil {
+		t.Fatalf("PrettyJSON failed: %v", err)
+	}
+	if want := "{\n  \"b\": 2,\n  \"a\": 1\n}"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+}
+
+func TestOrderedMapGob(t *testing.T) {
+	type snapshot struct {
+		Name string
+		Data *OrderedMap[string, []int]
+	}
+	om := NewMap[string, []int]()
+	om.Set("z", []int{1})
+	om.Set("a", []int{2, 3})
+	om.Set("m", nil)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot{Name: "s", Data: om}); err != nil {
+		t.Fatalf("GobEncode failed: %v", err)
+	}
+	var got snapshot
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("GobDecode failed: %v", err)
+	}
+	if keys := got.Data.Keys(); len(keys) != 3 || keys[0] != "z" || keys[1] != "a" || keys[2] != "m" {
+		t.Errorf("Expected order [z a m], got %v", keys)
+	}
+	if v, _ := got.Data.Get("a"); len(v) != 2 || v[1] != 3 {
+		t.Errorf("Expected [2 3], got %v", v)
+	}
+}
+
+func TestOrderedMapCapacity(t *testing.T) {
+	fill := func(om *OrderedMap[int, int]) {
+		for i := 0; i < 100; i++ {
+			om.Set(i, i)
+		}
+	}
+	presized := testing.AllocsPerRun(10, func() { fill(NewMapWithCapacity[int, int](100)) })
+	plain := testing.AllocsPerRun(10, func() { fill(NewMap[int, int]()) })
+	if presized >= plain/10 {
+		t.Errorf("Expected presized map to avoid per-entry allocations, got %v vs %v", presized, plain)
+	}
+
+	om := NewMapWithCapacity[int, int](100)
+	fill(om)
+	om.Grow(10)
+	for i := 100; i < 120; i++ {
+		om.Set(i, i)
+	}
+	if om.Len() != 120 || om.IndexOf(119) != 119 {
+		t.Errorf("Expected 120 ordered entries, got %d", om.Len())
+	}
+	if v, _ := om.Get(50); v != 50 {
+		t.Errorf("Expected entries to survive Grow, got %d", v)
+	}
+	om.Grow(0)
+	om.Grow(-1)
+}
+
+func TestOrderedMapPairs(t *testing.T) {
+	om := FromPairs([]Pair[string, string]{
+		{Key: "q", Value: "go"},
+		{Key: "page", Value: "1"},
+		{Key: "q", Value: "rust"},
+	})
+	entries := om.Entries()
+	if len(entries) != 2 || entries[0] != (Pair[string, string]{Key: "q", Value: "rust"}) || entries[1].Key != "page" {
+		t.Errorf("Unexpected entries %v", entries)
+	}
+	m := om.ToMap()
+	if len(m) != 2 || m["page"] != "1" {
+		t.Errorf("Unexpected map %v", m)
+	}
+	m["x"] = "y"
+	if om.Len() != 2 {
+		t.Errorf("Expected ToMap result to be independent")
+	}
+	if empty := FromPairs[int, int](nil); empty.Len() != 0 || len(empty.Entries()) != 0 {
+		t.Errorf("Expected empty map from nil pairs")
+	}
+}
+
+func TestOrderedMapEqual(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	a := FromPairs([]Pair[string, int]{{"x", 1}, {"y", 2}})
+	b := FromPairs([]Pair[string, int]{{"x", 1}, {"y", 2}})
+	c := FromPairs([]Pair[string, int]{{"y", 2}, {"x", 1}})
+	d := FromPairs([]Pair[string, int]{{"x", 1}, {"y", 3}})
+
+	if !a.Equal(b, eq) || !a.Equal(a, eq) {
+		t.Errorf("Expected identical maps to be equal")
+	}
+	if a.Equal(c, eq) || !a.EqualUnordered(c, eq) {
+		t.Errorf("Expected reordered maps to be equal only when unordered")
+	}
+	if a.Equal(d, eq) || a.EqualUnordered(d, eq) {
+		t.Errorf("Expected maps with different values to differ")
+	}
+	b.Set("z", 3)
+	if a.Equal(b, eq) || a.EqualUnordered(b, eq) {
+		t.Errorf("Expected maps with different sizes to differ")
+	}
+}
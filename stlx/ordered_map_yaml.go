@@ -0,0 +1,56 @@
+package stlx
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYAML 实现yaml.Marshaler接口，按插入顺序输出映射节点
+func (om *OrderedMap[K, V]) MarshalYAML() (interface{}, error) {
+	om.rlock()
+	defer om.runlock()
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for e := om.head; e != nil; e = e.next {
+		var key, value yaml.Node
+		if err := key.Encode(e.key); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(e.value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &key, &value)
+	}
+	return node, nil
+}
+
+// UnmarshalYAML 实现yaml.Unmarshaler接口，按文档中键出现的顺序插入，原有内容会被清空
+// 重复的键保留第一次出现的位置和最后一次出现的值，null 视为空映射
+func (om *OrderedMap[K, V]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	om.lock()
+	defer om.unlock()
+
+	om.clear()
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("yaml: line %d: expected mapping, got %s", node.Line, node.Tag)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var key K
+		var value V
+		if err := node.Content[i].Decode(&key); err != nil {
+			return err
+		}
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		om.set(key, value)
+	}
+	return nil
+}
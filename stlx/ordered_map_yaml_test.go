@@ -0,0 +1,49 @@
+package stlx
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestOrderedMapYAML(t *testing.T) {
+	type config struct {
+		Name    string                         `yaml:"name"`
+		Servers *OrderedMap[string, int]       `yaml:"servers"`
+		Nested  *OrderedMap[string, yaml.Node] `yaml:"nested"`
+	}
+	src := `name: app
+servers:
+  zeta: 3
+  alpha: 1
+  mid: 2
+nested:
+  b: [1, 2]
+  a: {x: 1}
+`
+	var cfg config
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if keys := cfg.Servers.Keys(); len(keys) != 3 || keys[0] != "zeta" || keys[1] != "alpha" || keys[2] != "mid" {
+		t.Errorf("Expected order [zeta alpha mid], got %v", keys)
+	}
+	if keys := cfg.Nested.Keys(); len(keys) != 2 || keys[0] != "b" {
+		t.Errorf("Expected nested order [b a], got %v", keys)
+	}
+
+	cfg.Nested = nil
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("MarshalYAML failed: %v", err)
+	}
+	want := "name: app\nservers:\n    zeta: 3\n    alpha: 1\n    mid: 2\nnested: null\n"
+	if string(out) != want {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+
+	om := NewMap[string, int]()
+	if err := yaml.Unmarshal([]byte("[1, 2]"), om); err == nil {
+		t.Errorf("Expected error for non-mapping input")
+	}
+}
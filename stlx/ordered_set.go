@@ -14,11 +14,32 @@ func NewSet[T comparable]() *OrderedSet[T] {
 	}
 }
 
+// NewSetFrom 按元素首次出现的顺序创建有序集合，重复的元素只保留第一个，可用于保序去重
+func NewSetFrom[T comparable](elements ...T) *OrderedSet[T] {
+	os := &OrderedSet[T]{
+		mp: NewMapWithCapacity[T, void](len(elements)),
+	}
+	for _, element := range elements {
+		os.add(element)
+	}
+	return os
+}
+
 // Add 添加元素到集合
 func (os *OrderedSet[T]) Add(element T) {
 	os.add(element)
 }
 
+// AddAll 按顺序添加多个元素，已存在的元素保持原有位置
+func (os *OrderedSet[T]) AddAll(elements ...T) {
+	os.lock()
+	defer os.unlock()
+
+	for _, element := range elements {
+		os.add(element)
+	}
+}
+
 // Del 从集合中移除元素
 func (os *OrderedSet[T]) Del(element T) {
 	os.mp.Del(element)
@@ -11,9 +11,7 @@ func (os *OrderedSet[T]) clear() {
 }
 
 func (os *OrderedSet[T]) vals() []T {
-	keys := make([]T, len(os.mp.keys))
-	copy(keys, os.mp.keys)
-	return keys
+	return os.mp.keys()
 }
 
 func (os *OrderedSet[T]) foreach(fn func(element T) bool) {
@@ -23,26 +21,26 @@ func (os *OrderedSet[T]) foreach(fn func(element T) bool) {
 }
 
 func (os *OrderedSet[T]) lock() {
-	os.mp.mu.Lock()
+	os.mp.lock()
 }
 
 func (os *OrderedSet[T]) unlock() {
-	os.mp.mu.Unlock()
+	os.mp.unlock()
 }
 
 func (os *OrderedSet[T]) rlock() {
-	os.mp.mu.RLock()
+	os.mp.rlock()
 }
 
 func (os *OrderedSet[T]) runlock() {
-	os.mp.mu.RUnlock()
+	os.mp.runlock()
 }
 
 // MarshalJSON 实现json.Marshaler接口
 func (os *OrderedSet[T]) MarshalJSON() ([]byte, error) {
-	os.lock()
-	defer os.unlock()
-	return json.Marshal(os.mp.keys)
+	os.rlock()
+	defer os.runlock()
+	return json.Marshal(os.mp.keys())
 }
 
 // UnmarshalJSON 实现json.Unmarshaler接口
@@ -66,3 +66,21 @@ func TestOrderedSet(t *testing.T) {
 		t.Errorf("For: Expected %v elements, got %v", expected, result)
 	}
 }
+
+func TestOrderedSetSlices(t *testing.T) {
+	set := NewSetFrom("b", "a", "b", "c", "a")
+	if vals := set.Vals(); len(vals) != 3 || vals[0] != "b" || vals[1] != "a" || vals[2] != "c" {
+		t.Errorf("Expected first-seen order [b a c], got %v", vals)
+	}
+	set.AddAll("d", "a", "e")
+	if vals := set.Vals(); len(vals) != 5 || vals[3] != "d" || vals[4] != "e" {
+		t.Errorf("Expected [b a c d e], got %v", vals)
+	}
+	set.Del("a")
+	if !set.Has("c") || set.Has("a") || set.Len() != 4 {
+		t.Errorf("Unexpected contents after Del: %v", set.Vals())
+	}
+	if empty := NewSetFrom[int](); empty.Len() != 0 || len(empty.Vals()) != 0 {
+		t.Errorf("Expected empty set")
+	}
+}
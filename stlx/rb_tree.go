@@ -0,0 +1,368 @@
+package stlx
+
+// rbNode 红黑树的节点
+type rbNode[K any, V any] struct {
+	key                 K
+	value               V
+	left, right, parent *rbNode[K, V]
+	red                 bool
+	size                int // 以该节点为根的子树中的节点数，哨兵为0，用于按排名查找
+}
+
+// rbTree 按 less 排序的红黑树，插入、删除、查找以及按排名查找都是 O(log n) 的
+// 所有叶子和根的父节点都指向同一个黑色的哨兵节点 leaf，省去对空指针的判断，该类型自身不加锁
+type rbTree[K any, V any] struct {
+	root   *rbNode[K, V]
+	leaf   *rbNode[K, V]
+	length int
+	less   func(a, b K) bool
+}
+
+func newRBTree[K any, V any](less func(a, b K) bool) *rbTree[K, V] {
+	t := &rbTree[K, V]{less: less}
+	t.clear()
+	return t
+}
+
+func (t *rbTree[K, V]) clear() {
+	t.leaf = &rbNode[K, V]{}
+	t.root = t.leaf
+	t.length = 0
+}
+
+// find 查找键对应的节点，不存在时返回 nil
+func (t *rbTree[K, V]) find(key K) *rbNode[K, V] {
+	x := t.root
+	for x != t.leaf {
+		switch {
+		case t.less(key, x.key):
+			x = x.left
+		case t.less(x.key, key):
+			x = x.right
+		default:
+			return x
+		}
+	}
+	return nil
+}
+
+// insert 写入键值对，键已存在时只更新值并返回 false
+func (t *rbTree[K, V]) insert(key K, value V) bool {
+	parent, x := t.leaf, t.root
+	for x != t.leaf {
+		parent = x
+		switch {
+		case t.less(key, x.key):
+			x = x.left
+		case t.less(x.key, key):
+			x = x.right
+		default:
+			x.value = value
+			return false
+		}
+	}
+	z := &rbNode[K, V]{key: key, value: value, left: t.leaf, right: t.leaf, parent: parent, red: true, size: 1}
+	switch {
+	case parent == t.leaf:
+		t.root = z
+	case t.less(key, parent.key):
+		parent.left = z
+	default:
+		parent.right = z
+	}
+	t.length++
+	for p := parent; p != t.leaf; p = p.parent {
+		p.size++
+	}
+	t.insertFixup(z)
+	return true
+}
+
+func (t *rbTree[K, V]) insertFixup(z *rbNode[K, V]) {
+	for z.parent.red {
+		grand := z.parent.parent
+		if z.parent == grand.left {
+			if uncle := grand.right; uncle.red {
+				z.parent.red, uncle.red, grand.red = false, false, true
+				z = grand
+				continue
+			}
+			if z == z.parent.right {
+				z = z.parent
+				t.rotateLeft(z)
+			}
+			z.parent.red, z.parent.parent.red = false, true
+			t.rotateRight(z.parent.parent)
+		} else {
+			if uncle := grand.left; uncle.red {
+				z.parent.red, uncle.red, grand.red = false, false, true
+				z = grand
+				continue
+			}
+			if z == z.parent.left {
+				z = z.parent
+				t.rotateRight(z)
+			}
+			z.parent.red, z.parent.parent.red = false, true
+			t.rotateLeft(z.parent.parent)
+		}
+	}
+	t.root.red = false
+}
+
+// remove 从树中删除节点
+func (t *rbTree[K, V]) remove(z *rbNode[K, V]) {
+	y, yRed := z, z.red
+	var x *rbNode[K, V]
+	switch {
+	case z.left == t.leaf:
+		x = z.right
+		t.transplant(z, z.right)
+	case z.right == t.leaf:
+		x = z.left
+		t.transplant(z, z.left)
+	default:
+		y = t.min(z.right)
+		yRed = y.red
+		x = y.right
+		if y.parent == z {
+			x.parent = y
+		} else {
+			t.transplant(y, y.right)
+			y.right = z.right
+			y.right.parent = y
+		}
+		t.transplant(z, y)
+		y.left = z.left
+		y.left.parent = y
+		y.red = z.red
+	}
+	t.length--
+	// 被删除的位置到根路径上的子树都少了一个节点
+	for p := x.parent; p != t.leaf; p = p.parent {
+		p.size = p.left.size + p.right.size + 1
+	}
+	if !yRed {
+		t.removeFixup(x)
+	}
+	t.leaf.parent = nil
+}
+
+func (t *rbTree[K, V]) removeFixup(x *rbNode[K, V]) {
+	for x != t.root && !x.red {
+		if x == x.parent.left {
+			w := x.parent.right
+			if w.red {
+				w.red, x.parent.red = false, true
+				t.rotateLeft(x.parent)
+				w = x.parent.right
+			}
+			if !w.left.red && !w.right.red {
+				w.red = true
+				x = x.parent
+				continue
+			}
+			if !w.right.red {
+				w.left.red, w.red = false, true
+				t.rotateRight(w)
+				w = x.parent.right
+			}
+			w.red, x.parent.red, w.right.red = x.parent.red, false, false
+			t.rotateLeft(x.parent)
+			x = t.root
+		} else {
+			w := x.parent.left
+			if w.red {
+				w.red, x.parent.red = false, true
+				t.rotateRight(x.parent)
+				w = x.parent.left
+			}
+			if !w.left.red && !w.right.red {
+				w.red = true
+				x = x.parent
+				continue
+			}
+			if !w.left.red {
+				w.right.red, w.red = false, true
+				t.rotateLeft(w)
+				w = x.parent.left
+			}
+			w.red, x.parent.red, w.left.red = x.parent.red, false, false
+			t.rotateRight(x.parent)
+			x = t.root
+		}
+	}
+	x.red = false
+}
+
+// transplant 用以 v 为根的子树替换以 u 为根的子树
+func (t *rbTree[K, V]) transplant(u, v *rbNode[K, V]) {
+	switch {
+	case u.parent == t.leaf:
+		t.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+	v.parent = u.parent
+}
+
+func (t *rbTree[K, V]) rotateLeft(x *rbNode[K, V]) {
+	y := x.right
+	x.right = y.left
+	if y.left != t.leaf {
+		y.left.parent = x
+	}
+	t.replaceChild(x, y)
+	y.left = x
+	x.parent = y
+	y.size = x.size
+	x.size = x.left.size + x.right.size + 1
+}
+
+func (t *rbTree[K, V]) rotateRight(x *rbNode[K, V]) {
+	y := x.left
+	x.left = y.right
+	if y.right != t.leaf {
+		y.right.parent = x
+	}
+	t.replaceChild(x, y)
+	y.right = x
+	x.parent = y
+	y.size = x.size
+	x.size = x.left.size + x.right.size + 1
+}
+
+// replaceChild 让 x 的父节点改为指向 y
+func (t *rbTree[K, V]) replaceChild(x, y *rbNode[K, V]) {
+	y.parent = x.parent
+	switch {
+	case x.parent == t.leaf:
+		t.root = y
+	case x == x.parent.left:
+		x.parent.left = y
+	default:
+		x.parent.right = y
+	}
+}
+
+// min 返回以 x 为根的子树中最小的节点，x 为哨兵时返回 nil
+func (t *rbTree[K, V]) min(x *rbNode[K, V]) *rbNode[K, V] {
+	if x == t.leaf {
+		return nil
+	}
+	for x.left != t.leaf {
+		x = x.left
+	}
+	return x
+}
+
+// max 返回以 x 为根的子树中最大的节点，x 为哨兵时返回 nil
+func (t *rbTree[K, V]) max(x *rbNode[K, V]) *rbNode[K, V] {
+	if x == t.leaf {
+		return nil
+	}
+	for x.right != t.leaf {
+		x = x.right
+	}
+	return x
+}
+
+// next 返回 x 的后继节点，x 是最大的节点时返回 nil
+func (t *rbTree[K, V]) next(x *rbNode[K, V]) *rbNode[K, V] {
+	if x.right != t.leaf {
+		return t.min(x.right)
+	}
+	p := x.parent
+	for p != t.leaf && x == p.right {
+		x, p = p, p.parent
+	}
+	if p == t.leaf {
+		return nil
+	}
+	return p
+}
+
+// prev 返回 x 的前驱节点，x 是最小的节点时返回 nil
+func (t *rbTree[K, V]) prev(x *rbNode[K, V]) *rbNode[K, V] {
+	if x.left != t.leaf {
+		return t.max(x.left)
+	}
+	p := x.parent
+	for p != t.leaf && x == p.left {
+		x, p = p, p.parent
+	}
+	if p == t.leaf {
+		return nil
+	}
+	return p
+}
+
+// ceiling 返回大于等于 key 的最小节点，inclusive 为 false 时返回大于 key 的最小节点，不存在时返回 nil
+func (t *rbTree[K, V]) ceiling(key K, inclusive bool) *rbNode[K, V] {
+	var found *rbNode[K, V]
+	x := t.root
+	for x != t.leaf {
+		switch {
+		case t.less(key, x.key):
+			found, x = x, x.left
+		case t.less(x.key, key) || !inclusive:
+			x = x.right
+		default:
+			return x
+		}
+	}
+	return found
+}
+
+// floor 返回小于等于 key 的最大节点，inclusive 为 false 时返回小于 key 的最大节点，不存在时返回 nil
+func (t *rbTree[K, V]) floor(key K, inclusive bool) *rbNode[K, V] {
+	var found *rbNode[K, V]
+	x := t.root
+	for x != t.leaf {
+		switch {
+		case t.less(x.key, key):
+			found, x = x, x.right
+		case t.less(key, x.key) || !inclusive:
+			x = x.left
+		default:
+			return x
+		}
+	}
+	return found
+}
+
+// rank 返回小于 key 的节点数
+func (t *rbTree[K, V]) rank(key K) int {
+	r := 0
+	x := t.root
+	for x != t.leaf {
+		if t.less(x.key, key) {
+			r += x.left.size + 1
+			x = x.right
+		} else {
+			x = x.left
+		}
+	}
+	return r
+}
+
+// at 返回从小到大第 i 个节点，i 越界时返回 nil
+func (t *rbTree[K, V]) at(i int) *rbNode[K, V] {
+	if i < 0 || i >= t.length {
+		return nil
+	}
+	x := t.root
+	for {
+		switch l := x.left.size; {
+		case i < l:
+			x = x.left
+		case i > l:
+			i -= l + 1
+			x = x.right
+		default:
+			return x
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package stlx
+
+import "sync"
+
+// SortedMap 是一个协程安全的按键排序的映射，基于红黑树实现
+// 查找、插入和删除都是 O(log n) 的，遍历时按键从小到大的顺序进行
+// 键的相等由比较函数决定，!less(a, b) && !less(b, a) 时视为同一个键
+type SortedMap[K any, V any] struct {
+	mu   sync.RWMutex
+	tree *rbTree[K, V]
+}
+
+// NewSortedMap 创建一个新的排序映射
+// less 函数用于比较两个键的大小，如果 a < b 则返回 true，为 nil 时返回 nil
+func NewSortedMap[K any, V any](less func(a, b K) bool) *SortedMap[K, V] {
+	if less == nil {
+		return nil
+	}
+	return &SortedMap[K, V]{tree: newRBTree[K, V](less)}
+}
+
+// Set 添加或更新键值对
+func (sm *SortedMap[K, V]) Set(key K, value V) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.set(key, value)
+}
+
+// Get 获取键对应的值
+func (sm *SortedMap[K, V]) Get(key K) (V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if n := sm.tree.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has 判断键是否存在
+func (sm *SortedMap[K, V]) Has(key K) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.tree.find(key) != nil
+}
+
+// Del 删除键对应的值，并返回被删除的值
+func (sm *SortedMap[K, V]) Del(key K) V {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	n := sm.tree.find(key)
+	if n == nil {
+		var zero V
+		return zero
+	}
+	sm.tree.remove(n)
+	return n.value
+}
+
+// Len 返回映射中的键值对数量
+func (sm *SortedMap[K, V]) Len() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.tree.length
+}
+
+// Clear 清空映射
+func (sm *SortedMap[K, V]) Clear() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.clear()
+}
+
+// Keys 返回所有的键，按从小到大排列
+func (sm *SortedMap[K, V]) Keys() []K {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	keys := make([]K, 0, sm.tree.length)
+	sm.foreach(func(key K, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Vals 返回所有的值，按键从小到大排列
+func (sm *SortedMap[K, V]) Vals() []V {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	values := make([]V, 0, sm.tree.length)
+	sm.foreach(func(key K, value V) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// For 按键从小到大遍历所有键值对
+// 如果回调函数返回 false，则停止遍历
+func (sm *SortedMap[K, V]) For(fn func(key K, value V) bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	sm.foreach(fn)
+}
+
+// ForReverse 按键从大到小遍历所有键值对
+func (sm *SortedMap[K, V]) ForReverse(fn func(key K, value V) bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for n := sm.tree.max(sm.tree.root); n != nil; n = sm.tree.prev(n) {
+		if !fn(n.key, n.value) {
+			break
+		}
+	}
+}
+
+// Min 返回最小的键及其值，映射为空时返回 false
+func (sm *SortedMap[K, V]) Min() (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.min(sm.tree.root))
+}
+
+// Max 返回最大的键及其值，映射为空时返回 false
+func (sm *SortedMap[K, V]) Max() (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.max(sm.tree.root))
+}
+
+// Floor 返回小于等于 key 的最大键及其值，不存在时返回 false
+func (sm *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.floor(key, true))
+}
+
+// Ceiling 返回大于等于 key 的最小键及其值，不存在时返回 false
+func (sm *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.ceiling(key, true))
+}
+
+// Lower 返回小于 key 的最大键及其值，不存在时返回 false
+func (sm *SortedMap[K, V]) Lower(key K) (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.floor(key, false))
+}
+
+// Higher 返回大于 key 的最小键及其值，不存在时返回 false
+func (sm *SortedMap[K, V]) Higher(key K) (K, V, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.entry(sm.tree.ceiling(key, false))
+}
+
+// Range 按从小到大的顺序遍历键在 [from, to) 范围内的键值对，fn 返回 false 时停止
+// 定位起点是 O(log n) 的，之后每个键值对是均摊 O(1) 的
+func (sm *SortedMap[K, V]) Range(from, to K, fn func(key K, value V) bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	for n := sm.tree.ceiling(from, true); n != nil && sm.tree.less(n.key, to); n = sm.tree.next(n) {
+		if !fn(n.key, n.value) {
+			break
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package stlx
+
+func (sm *SortedMap[K, V]) clear() {
+	sm.tree.clear()
+}
+
+func (sm *SortedMap[K, V]) set(key K, value V) {
+	sm.tree.insert(key, value)
+}
+
+func (sm *SortedMap[K, V]) foreach(fn func(key K, value V) bool) {
+	for n := sm.tree.min(sm.tree.root); n != nil; n = sm.tree.next(n) {
+		if !fn(n.key, n.value) {
+			break
+		}
+	}
+}
+
+// entry 返回节点的键和值，节点为 nil 时返回 false
+func (sm *SortedMap[K, V]) entry(n *rbNode[K, V]) (K, V, bool) {
+	if n == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
+
+func (sm *SortedMap[K, V]) lock() {
+	sm.mu.Lock()
+}
+
+func (sm *SortedMap[K, V]) unlock() {
+	sm.mu.Unlock()
+}
+
+func (sm *SortedMap[K, V]) rlock() {
+	sm.mu.RLock()
+}
+
+func (sm *SortedMap[K, V]) runlock() {
+	sm.mu.RUnlock()
+}
+
+// MarshalJSON 实现json.Marshaler接口，按键从小到大输出
+func (sm *SortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return marshalMap[K, V](sm)
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口，原有内容会被清空
+func (sm *SortedMap[K, V]) UnmarshalJSON(data []byte) error {
+	return unmarshalMap[K, V](sm, data)
+}
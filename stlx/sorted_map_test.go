@@ -0,0 +1,184 @@
+package stlx
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkRBTree 检查红黑树的性质：根为黑色、红色节点的子节点为黑色、每条路径上的黑色节点数相同、父指针和子树大小一致
+func checkRBTree[K any, V any](t *testing.T, tree *rbTree[K, V]) {
+	t.Helper()
+	if tree.root.red {
+		t.Fatalf("Root is red")
+	}
+	var walk func(n *rbNode[K, V]) (int, int)
+	walk = func(n *rbNode[K, V]) (int, int) {
+		if n == tree.leaf {
+			return 1, 0
+		}
+		if n.red && (n.left.red || n.right.red) {
+			t.Fatalf("Red node %v has a red child", n.key)
+		}
+		for _, c := range []*rbNode[K, V]{n.left, n.right} {
+			if c != tree.leaf && c.parent != n {
+				t.Fatalf("Broken parent pointer at %v", c.key)
+			}
+		}
+		lb, ln := walk(n.left)
+		rb, rn := walk(n.right)
+		if lb != rb {
+			t.Fatalf("Black height mismatch at %v", n.key)
+		}
+		if n.size != ln+rn+1 {
+			t.Fatalf("Size of %v is %d, expected %d", n.key, n.size, ln+rn+1)
+		}
+		if !n.red {
+			lb++
+		}
+		return lb, ln + rn + 1
+	}
+	if _, n := walk(tree.root); n != tree.length {
+		t.Fatalf("Expected %d nodes, counted %d", tree.length, n)
+	}
+}
+
+func TestSortedMap(t *testing.T) {
+	if NewSortedMap[int, int](nil) != nil {
+		t.Errorf("Expected nil map without comparator")
+	}
+	sm := NewSortedMap[int, string](func(a, b int) bool { return a < b })
+	if _, _, ok := sm.Min(); ok {
+		t.Errorf("Expected Min on empty map to fail")
+	}
+	for _, k := range []int{5, 3, 8, 1, 4, 7, 9} {
+		sm.Set(k, string(rune('a'+k)))
+	}
+	sm.Set(3, "three")
+
+	if keys := sm.Keys(); !sort.IntsAreSorted(keys) || len(keys) != 7 {
+		t.Errorf("Expected sorted keys, got %v", keys)
+	}
+	if v, ok := sm.Get(3); !ok || v != "three" {
+		t.Errorf("Expected updated value, got %s", v)
+	}
+	if k, _, _ := sm.Min(); k != 1 {
+		t.Errorf("Expected min 1, got %d", k)
+	}
+	if k, _, _ := sm.Max(); k != 9 {
+		t.Errorf("Expected max 9, got %d", k)
+	}
+	if v := sm.Del(5); v != "f" || sm.Has(5) || sm.Len() != 6 {
+		t.Errorf("Expected 5 to be deleted, got %s", v)
+	}
+	var reversed []int
+	sm.ForReverse(func(key int, value string) bool {
+		reversed = append(reversed, key)
+		return len(reversed) < 2
+	})
+	if len(reversed) != 2 || reversed[0] != 9 || reversed[1] != 8 {
+		t.Errorf("Expected [9 8], got %v", reversed)
+	}
+
+	data, err := json.Marshal(sm)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if want := `{"1":"b","3":"three","4":"e","7":"h","8":"i","9":"j"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+	sm2 := NewSortedMap[int, string](func(a, b int) bool { return a < b })
+	if err := json.Unmarshal([]byte(`{"9":"z","2":"y"}`), sm2); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if keys := sm2.Keys(); len(keys) != 2 || keys[0] != 2 {
+		t.Errorf("Expected [2 9], got %v", keys)
+	}
+}
+
+func TestSortedMapRandom(t *testing.T) {
+	sm := NewSortedMap[int, int](func(a, b int) bool { return a < b })
+	ref := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			sm.Del(k)
+			delete(ref, k)
+		} else {
+			sm.Set(k, i)
+			ref[k] = i
+		}
+		if i%250 == 0 {
+			checkRBTree(t, sm.tree)
+		}
+	}
+	checkRBTree(t, sm.tree)
+	if sm.Len() != len(ref) {
+		t.Fatalf("Expected %d entries, got %d", len(ref), sm.Len())
+	}
+	prev := -1
+	sm.For(func(key, value int) bool {
+		if key <= prev || ref[key] != value {
+			t.Fatalf("Unexpected entry %d=%d after %d", key, value, prev)
+		}
+		prev = key
+		return true
+	})
+	sm.Clear()
+	if sm.Len() != 0 || len(sm.Keys()) != 0 {
+		t.Errorf("Expected empty map after Clear")
+	}
+}
+
+func TestSortedMapNavigation(t *testing.T) {
+	sm := NewSortedMap[int, string](func(a, b int) bool { return a < b })
+	for _, k := range []int{10, 20, 30, 40} {
+		sm.Set(k, "")
+	}
+	cases := []struct {
+		name string
+		fn   func(int) (int, string, bool)
+		in   int
+		want int
+		ok   bool
+	}{
+		{"Floor", sm.Floor, 25, 20, true},
+		{"Floor", sm.Floor, 20, 20, true},
+		{"Floor", sm.Floor, 5, 0, false},
+		{"Ceiling", sm.Ceiling, 25, 30, true},
+		{"Ceiling", sm.Ceiling, 30, 30, true},
+		{"Ceiling", sm.Ceiling, 45, 0, false},
+		{"Lower", sm.Lower, 20, 10, true},
+		{"Lower", sm.Lower, 10, 0, false},
+		{"Higher", sm.Higher, 20, 30, true},
+		{"Higher", sm.Higher, 40, 0, false},
+	}
+	for _, c := range cases {
+		if k, _, ok := c.fn(c.in); ok != c.ok || k != c.want {
+			t.Errorf("%s(%d) = %d, %v, want %d, %v", c.name, c.in, k, ok, c.want, c.ok)
+		}
+	}
+
+	var keys []int
+	sm.Range(15, 40, func(key int, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 2 || keys[0] != 20 || keys[1] != 30 {
+		t.Errorf("Expected [20 30], got %v", keys)
+	}
+	keys = nil
+	sm.Range(10, 100, func(key int, value string) bool {
+		keys = append(keys, key)
+		return key < 30
+	})
+	if len(keys) != 3 || keys[0] != 10 {
+		t.Errorf("Expected [10 20 30], got %v", keys)
+	}
+	sm.Range(50, 60, func(key int, value string) bool {
+		t.Errorf("Expected empty range, got %d", key)
+		return true
+	})
+}
@@ -0,0 +1,89 @@
+package stlx
+
+// SortedSet 是一个协程安全的按比较函数排序的集合，基于红黑树实现
+// 除了查找、插入和删除外，按排名查找和计算排名也是 O(log n) 的，适合排行榜一类的内存索引
+type SortedSet[T any] struct {
+	mp *SortedMap[T, void]
+}
+
+// NewSortedSet 创建一个新的排序集合
+// less 函数用于比较两个元素的大小，如果 a < b 则返回 true，为 nil 时返回 nil
+func NewSortedSet[T any](less func(a, b T) bool) *SortedSet[T] {
+	if less == nil {
+		return nil
+	}
+	return &SortedSet[T]{mp: NewSortedMap[T, void](less)}
+}
+
+// Add 添加元素到集合
+func (ss *SortedSet[T]) Add(element T) {
+	ss.mp.Set(element, void{})
+}
+
+// Del 从集合中移除元素
+func (ss *SortedSet[T]) Del(element T) {
+	ss.mp.Del(element)
+}
+
+// Has 检查元素是否在集合中
+func (ss *SortedSet[T]) Has(element T) bool {
+	return ss.mp.Has(element)
+}
+
+// Len 返回集合大小
+func (ss *SortedSet[T]) Len() int {
+	return ss.mp.Len()
+}
+
+// Clear 清空集合
+func (ss *SortedSet[T]) Clear() {
+	ss.mp.Clear()
+}
+
+// Vals 返回所有元素，按从小到大排列
+func (ss *SortedSet[T]) Vals() []T {
+	return ss.mp.Keys()
+}
+
+// For 按从小到大的顺序遍历集合中的所有元素
+func (ss *SortedSet[T]) For(fn func(element T) bool) {
+	ss.mp.For(func(key T, value void) bool {
+		return fn(key)
+	})
+}
+
+// Min 返回最小的元素，集合为空时返回 false
+func (ss *SortedSet[T]) Min() (T, bool) {
+	element, _, ok := ss.mp.Min()
+	return element, ok
+}
+
+// Max 返回最大的元素，集合为空时返回 false
+func (ss *SortedSet[T]) Max() (T, bool) {
+	element, _, ok := ss.mp.Max()
+	return element, ok
+}
+
+// Rank 返回集合中小于 element 的元素个数，element 在集合中时即为它从0开始的排名
+func (ss *SortedSet[T]) Rank(element T) int {
+	ss.rlock()
+	defer ss.runlock()
+
+	return ss.mp.tree.rank(element)
+}
+
+// At 返回从小到大排名第 i 的元素，i 从0开始，越界时返回 false
+func (ss *SortedSet[T]) At(i int) (T, bool) {
+	ss.rlock()
+	defer ss.runlock()
+
+	element, _, ok := ss.mp.entry(ss.mp.tree.at(i))
+	return element, ok
+}
+
+// Range 按从小到大的顺序遍历 [from, to) 范围内的元素，fn 返回 false 时停止
+func (ss *SortedSet[T]) Range(from, to T, fn func(element T) bool) {
+	ss.mp.Range(from, to, func(key T, value void) bool {
+		return fn(key)
+	})
+}
@@ -0,0 +1,50 @@
+package stlx
+
+func (ss *SortedSet[T]) add(element T) {
+	ss.mp.set(element, void{})
+}
+
+func (ss *SortedSet[T]) clear() {
+	ss.mp.clear()
+}
+
+func (ss *SortedSet[T]) vals() []T {
+	elements := make([]T, 0, ss.mp.tree.length)
+	ss.foreach(func(element T) bool {
+		elements = append(elements, element)
+		return true
+	})
+	return elements
+}
+
+func (ss *SortedSet[T]) foreach(fn func(element T) bool) {
+	ss.mp.foreach(func(key T, value void) bool {
+		return fn(key)
+	})
+}
+
+func (ss *SortedSet[T]) lock() {
+	ss.mp.lock()
+}
+
+func (ss *SortedSet[T]) unlock() {
+	ss.mp.unlock()
+}
+
+func (ss *SortedSet[T]) rlock() {
+	ss.mp.rlock()
+}
+
+func (ss *SortedSet[T]) runlock() {
+	ss.mp.runlock()
+}
+
+// MarshalJSON 实现json.Marshaler接口，按从小到大输出为数组
+func (ss *SortedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalCollection[T](ss)
+}
+
+// UnmarshalJSON 实现json.Unmarshaler接口，原有内容会被清空
+func (ss *SortedSet[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalCollection[T](ss, data)
+}
@@ -0,0 +1,103 @@
+package stlx
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type score struct {
+	Name   string
+	Points int
+}
+
+func TestSortedSet(t *testing.T) {
+	// 分数从高到低，分数相同时按名字排序
+	board := NewSortedSet(func(a, b score) bool {
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		return a.Name < b.Name
+	})
+	board.Add(score{"bob", 80})
+	board.Add(score{"amy", 95})
+	board.Add(score{"cat", 80})
+	board.Add(score{"dan", 60})
+	board.Add(score{"amy", 95})
+
+	if board.Len() != 4 || !board.Has(score{"cat", 80}) {
+		t.Errorf("Expected 4 distinct scores")
+	}
+	if top, _ := board.Min(); top.Name != "amy" {
+		t.Errorf("Expected amy on top, got %v", top)
+	}
+	if last, _ := board.Max(); last.Name != "dan" {
+		t.Errorf("Expected dan last, got %v", last)
+	}
+	if r := board.Rank(score{"cat", 80}); r != 2 {
+		t.Errorf("Expected cat at rank 2, got %d", r)
+	}
+	if r := board.Rank(score{"eve", 70}); r != 3 {
+		t.Errorf("Expected 70 points to rank 3, got %d", r)
+	}
+	if s, ok := board.At(1); !ok || s.Name != "bob" {
+		t.Errorf("Expected bob at 1, got %v", s)
+	}
+	if _, ok := board.At(4); ok {
+		t.Errorf("Expected out of range rank to fail")
+	}
+
+	var names []string
+	board.Range(score{"", 90}, score{"", 60}, func(s score) bool {
+		names = append(names, s.Name)
+		return true
+	})
+	if len(names) != 2 || names[0] != "bob" || names[1] != "cat" {
+		t.Errorf("Expected [bob cat] within [90, 60), got %v", names)
+	}
+
+	board.Del(score{"amy", 95})
+	if top, _ := board.At(0); top.Name != "bob" {
+		t.Errorf("Expected bob on top after deletion, got %v", top)
+	}
+}
+
+func TestSortedSetRank(t *testing.T) {
+	set := NewSortedSet(func(a, b int) bool { return a < b })
+	ref := make(map[int]bool)
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 3000; i++ {
+		v := r.Intn(300)
+		if r.Intn(3) == 0 {
+			set.Del(v)
+			delete(ref, v)
+		} else {
+			set.Add(v)
+			ref[v] = true
+		}
+	}
+	checkRBTree(t, set.mp.tree)
+	sorted := make([]int, 0, len(ref))
+	for v := range ref {
+		sorted = append(sorted, v)
+	}
+	sort.Ints(sorted)
+	for i, v := range sorted {
+		if got := set.Rank(v); got != i {
+			t.Fatalf("Rank(%d) = %d, want %d", v, got, i)
+		}
+		if got, _ := set.At(i); got != v {
+			t.Fatalf("At(%d) = %d, want %d", i, got, v)
+		}
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	set2 := NewSortedSet(func(a, b int) bool { return a < b })
+	if err := json.Unmarshal(data, set2); err != nil || set2.Len() != len(sorted) {
+		t.Errorf("Expected JSON round-trip, err: %v", err)
+	}
+}